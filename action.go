@@ -3,6 +3,8 @@ package hxcmp
 import (
 	"encoding/json"
 	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/a-h/templ"
 )
@@ -72,3 +74,31 @@ func WireAttrs(path, method, encoded string) templ.Attributes {
 
 	return attrs
 }
+
+// ActionURL builds a plain URL for a component action with props encoded in
+// the query string.
+//
+// Generated code registers every action under its own path and reads the
+// "p" query parameter before the form body, so the returned URL works for
+// any method. Use it for <a href> fallbacks, hx-get inside hx-on handlers,
+// emails, or Result.PushURL:
+//
+//	func (c *Counter) URLIncrement(props CounterProps) string {
+//	    path, encoded := c.buildActionURL("increment", props)
+//	    return hxcmp.ActionURL(path, encoded)
+//	}
+func ActionURL(path, encoded string) string {
+	if encoded == "" {
+		return path
+	}
+	return path + "?p=" + url.QueryEscape(encoded)
+}
+
+// AbsoluteURL joins a base URL (scheme and host, optionally with a mount
+// path) with a component URL returned by ActionURL:
+//
+//	hxcmp.AbsoluteURL("https://example.com", "/_hxc/counter-1a2b3c4d/?p=...")
+//	// "https://example.com/_hxc/counter-1a2b3c4d/?p=..."
+func AbsoluteURL(base, actionURL string) string {
+	return strings.TrimSuffix(base, "/") + "/" + strings.TrimPrefix(actionURL, "/")
+}
@@ -80,3 +80,31 @@ func TestActionBuilderMethod(t *testing.T) {
 		t.Errorf("method = %q, want %q", action.method, http.MethodDelete)
 	}
 }
+
+func TestActionURL(t *testing.T) {
+	if got := ActionURL("/_hxc/test/save", ""); got != "/_hxc/test/save" {
+		t.Errorf("ActionURL() without props = %q", got)
+	}
+	if got := ActionURL("/_hxc/test/save", "abc.def"); got != "/_hxc/test/save?p=abc.def" {
+		t.Errorf("ActionURL() = %q", got)
+	}
+	if got := ActionURL("/_hxc/test/", "a+b/c="); got != "/_hxc/test/?p=a%2Bb%2Fc%3D" {
+		t.Errorf("ActionURL() should query-escape props, got %q", got)
+	}
+}
+
+func TestAbsoluteURL(t *testing.T) {
+	tests := []struct {
+		base string
+		want string
+	}{
+		{"https://example.com", "https://example.com/_hxc/test/?p=x"},
+		{"https://example.com/", "https://example.com/_hxc/test/?p=x"},
+		{"https://example.com/app", "https://example.com/app/_hxc/test/?p=x"},
+	}
+	for _, tt := range tests {
+		if got := AbsoluteURL(tt.base, "/_hxc/test/?p=x"); got != tt.want {
+			t.Errorf("AbsoluteURL(%q) = %q, want %q", tt.base, got, tt.want)
+		}
+	}
+}
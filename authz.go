@@ -0,0 +1,98 @@
+package hxcmp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/a-h/templ"
+)
+
+// AuthRequest describes a policy check for a request to a component.
+type AuthRequest struct {
+	Component string // Component name, as passed to hxcmp.New
+	Action    string // Action name; empty for the render endpoint
+	Policy    string // Policy named with Require
+	Props     any    // Decoded and hydrated props
+}
+
+// Authorizer decides whether the user in ctx satisfies a policy. It returns
+// nil to allow the request and ErrForbidden, or an error wrapping it, to
+// deny it. Other errors are passed to OnError as they are.
+type Authorizer func(ctx context.Context, req AuthRequest) error
+
+// Require names policies a user must satisfy to render the component or run
+// any of its actions. It is the default for actions that don't call
+// ActionBuilder.Require:
+//
+//	c := &Billing{Component: hxcmp.New[Props]("billing")}
+//	c.Require("billing:read")
+//	c.Action("refund", c.handleRefund).Require("billing:refund")
+//
+// Policies are checked by Registry.Authorizer after props are decoded and
+// Hydrate has run. Requests are denied when no Authorizer is set.
+func (c *Component[P]) Require(policies ...string) *Component[P] {
+	c.policies = append(c.policies, policies...)
+	return c
+}
+
+// Require names policies a user must satisfy to run this action. They
+// replace the component's default policies for the action.
+func (ab *ActionBuilder) Require(policies ...string) *ActionBuilder {
+	ab.action.policies = append(ab.action.policies, policies...)
+	return ab
+}
+
+// DisableWhenDenied makes the generated Wire<Action>For method return a
+// disabled marker, instead of empty attributes, when the action is denied:
+//
+//	c.Action("delete", c.handleDelete).Require("todo:delete").DisableWhenDenied()
+//
+//	<button { c.WireDeleteFor(ctx, props)... }>Delete</button>
+//
+// renders a disabled button for users without the "todo:delete" policy.
+func (ab *ActionBuilder) DisableWhenDenied() *ActionBuilder {
+	ab.action.disableDenied = true
+	return ab
+}
+
+// SetAuthorizer is called by the registry during component registration to
+// install the policy check used by Authorize.
+//
+// User code should not call this directly.
+func (c *Component[P]) SetAuthorizer(authorize Authorizer) {
+	c.authorize = authorize
+}
+
+// Authorize checks the policies that apply to an action, or to the render
+// endpoint when action is empty, and returns the first denial. Generated
+// dispatch calls it after Hydrate.
+func (c *Component[P]) Authorize(ctx context.Context, action string, props P) error {
+	policies := c.policies
+	if a, ok := c.actions[action]; ok && len(a.policies) > 0 {
+		policies = a.policies
+	}
+	for _, policy := range policies {
+		if c.authorize == nil {
+			return fmt.Errorf("%w: no Authorizer to check policy %q", ErrForbidden, policy)
+		}
+		req := AuthRequest{Component: c.name, Action: action, Policy: policy, Props: props}
+		if err := c.authorize(ctx, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeniedAttrs reports whether ctx is denied the action and, if so, the
+// attributes the generated Wire<Action>For method returns in place of the
+// wiring: none, or a disabled marker after DisableWhenDenied. Any error from
+// the Authorizer denies the action.
+func (c *Component[P]) DeniedAttrs(ctx context.Context, action string, props P) (templ.Attributes, bool) {
+	if c.Authorize(ctx, action, props) == nil {
+		return nil, false
+	}
+	if a, ok := c.actions[action]; ok && a.disableDenied {
+		return templ.Attributes{"disabled": true, "aria-disabled": "true"}, true
+	}
+	return templ.Attributes{}, true
+}
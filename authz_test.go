@@ -0,0 +1,104 @@
+package hxcmp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestAuthorize(t *testing.T) {
+	errDB := errors.New("db down")
+
+	reg := NewRegistry(make([]byte, 32))
+	reg.Authorizer = func(ctx context.Context, req AuthRequest) error {
+		switch req.Props.(widgetProps).ID {
+		case "admin":
+			return nil
+		case "broken":
+			return errDB
+		}
+		if req.Policy == "widget:write" {
+			return fmt.Errorf("%w: %s needs %s", ErrForbidden, req.Action, req.Policy)
+		}
+		return nil
+	}
+	c := newWidget("widget")
+	c.Require("widget:read")
+	c.Action("save", c.handleSave).Require("widget:write")
+	reg.Add(c)
+
+	tests := []struct {
+		name   string
+		method string
+		action string
+		id     string
+		want   int
+	}{
+		{"render allowed", http.MethodGet, "", "1", http.StatusOK},
+		{"default policy", http.MethodDelete, "remove", "1", http.StatusOK},
+		{"action denied", http.MethodPost, "save", "1", http.StatusForbidden},
+		{"action allowed", http.MethodPost, "save", "admin", http.StatusOK},
+		{"authorizer error", http.MethodGet, "", "broken", http.StatusInternalServerError},
+		{"hydrate first", http.MethodPost, "save", "missing", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, widgetURL(t, reg, c, tt.action, widgetProps{ID: tt.id}), nil)
+			reg.Handler().ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthorizeWithoutAuthorizer(t *testing.T) {
+	ctx := context.Background()
+
+	c := newWidget("widget")
+	if err := c.Authorize(ctx, "save", widgetProps{}); err != nil {
+		t.Errorf("Authorize() without policies = %v, want nil", err)
+	}
+
+	c.Action("save", c.handleSave).Require("widget:write")
+	if err := c.Authorize(ctx, "save", widgetProps{}); !IsForbidden(err) {
+		t.Errorf("Authorize() = %v, want ErrForbidden", err)
+	}
+	if err := c.Authorize(ctx, "", widgetProps{}); err != nil {
+		t.Errorf("Authorize(render) = %v, want nil", err)
+	}
+}
+
+func TestDeniedAttrs(t *testing.T) {
+	ctx := context.Background()
+	c := newWidget("widget")
+	c.SetAuthorizer(func(ctx context.Context, req AuthRequest) error {
+		return ErrForbidden
+	})
+	c.Action("save", c.handleSave).Require("widget:write")
+	c.Action("remove", c.handleSave).Method(http.MethodDelete).Require("widget:write").DisableWhenDenied()
+
+	tests := []struct {
+		action string
+		want   any
+		denied bool
+	}{
+		{"save", map[string]any{}, true},
+		{"remove", map[string]any{"disabled": true, "aria-disabled": "true"}, true},
+		{"", nil, false},
+	}
+	for _, tt := range tests {
+		attrs, denied := c.DeniedAttrs(ctx, tt.action, widgetProps{})
+		if denied != tt.denied {
+			t.Errorf("DeniedAttrs(%q) denied = %v, want %v", tt.action, denied, tt.denied)
+		}
+		if tt.want != nil && !reflect.DeepEqual(map[string]any(attrs), tt.want) {
+			t.Errorf("DeniedAttrs(%q) = %v, want %v", tt.action, attrs, tt.want)
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package hxcmp
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// DefaultCacheEntries is the number of renders a registry's RenderCache
+// holds until SetMaxEntries changes it.
+const DefaultCacheEntries = 1000
+
+// RenderCache is an in-memory LRU of rendered component output, shared by
+// the components of a registry so that an action of one component can evict
+// the renders of another. Get it with Registry.Cache.
+type RenderCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List // Of *cacheEntry, most recently used first
+	entries    map[string]*list.Element
+	tags       map[string]map[string]struct{} // tag -> keys
+	now        func() time.Time
+}
+
+type cacheEntry struct {
+	key     string
+	body    []byte
+	tags    []string
+	expires time.Time
+}
+
+// NewRenderCache creates a cache holding up to maxEntries renders. Registries
+// create their own; use this to test code that takes a *RenderCache.
+func NewRenderCache(maxEntries int) *RenderCache {
+	return &RenderCache{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+		tags:       make(map[string]map[string]struct{}),
+		now:        time.Now,
+	}
+}
+
+// SetMaxEntries changes how many renders the cache holds, evicting the least
+// recently used ones beyond n.
+func (c *RenderCache) SetMaxEntries(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.maxEntries = n
+	c.evict()
+}
+
+// Len returns the number of cached renders, including expired ones not yet
+// evicted.
+func (c *RenderCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Invalidate evicts the renders cached with any of tags and returns how
+// many were evicted. Actions invalidate through Result.Invalidate and
+// Result.Trigger; call it directly when data changes outside a request:
+//
+//	reg.Cache().Invalidate("todo-updated")
+func (c *RenderCache) Invalidate(tags ...string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := 0
+	for _, tag := range tags {
+		for key := range c.tags[tag] {
+			c.remove(c.entries[key])
+			n++
+		}
+	}
+	return n
+}
+
+// Purge evicts every cached render.
+func (c *RenderCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.order.Init()
+	c.entries = make(map[string]*list.Element)
+	c.tags = make(map[string]map[string]struct{})
+}
+
+// get returns the unexpired render cached under key.
+func (c *RenderCache) get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*cacheEntry)
+	if !c.now().Before(e.expires) {
+		c.remove(el)
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return e.body, true
+}
+
+// set caches body under key for ttl, replacing any render cached under it.
+func (c *RenderCache) set(key string, body []byte, ttl time.Duration, tags []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		c.remove(el)
+	}
+	e := &cacheEntry{key: key, body: body, tags: tags, expires: c.now().Add(ttl)}
+	c.entries[key] = c.order.PushFront(e)
+	for _, tag := range tags {
+		if c.tags[tag] == nil {
+			c.tags[tag] = make(map[string]struct{})
+		}
+		c.tags[tag][key] = struct{}{}
+	}
+	c.evict()
+}
+
+// evict removes the least recently used renders beyond maxEntries. The
+// caller holds c.mu.
+func (c *RenderCache) evict() {
+	for c.order.Len() > max(c.maxEntries, 0) {
+		c.remove(c.order.Back())
+	}
+}
+
+// remove removes el and its tag references. The caller holds c.mu.
+func (c *RenderCache) remove(el *list.Element) {
+	e := c.order.Remove(el).(*cacheEntry)
+	delete(c.entries, e.key)
+	for _, tag := range e.tags {
+		delete(c.tags[tag], e.key)
+		if len(c.tags[tag]) == 0 {
+			delete(c.tags, tag)
+		}
+	}
+}
+
+// Cache caches the component's render output for ttl, for components that
+// are expensive to hydrate and render but change rarely:
+//
+//	c := &Stats{Component: hxcmp.New[Props]("stats")}
+//	c.Cache(time.Minute, "todo-updated")
+//
+// Renders are cached by component, props and CacheVary key, and a hit skips
+// Hydrate and Render. Entries carry tags and are evicted when an action's
+// Result triggers an event named like one of them, or names it with
+// Invalidate. Only the render endpoint is cached; action responses are not.
+//
+// Components with Require policies still run Hydrate and the policy check
+// on every request, so policies see hydrated props; a hit skips only
+// Render. Render output that depends on the user must be separated with
+// CacheVary.
+func (c *Component[P]) Cache(ttl time.Duration, tags ...string) *Component[P] {
+	c.cacheTTL = ttl
+	c.cacheTags = tags
+	return c
+}
+
+// CacheVary adds the string vary returns for a request to the cache key,
+// for render output that depends on more than props, such as the current
+// user:
+//
+//	c.Cache(time.Minute).CacheVary(func(r *http.Request) string {
+//	    return auth.UserFrom(r.Context()).ID
+//	})
+func (c *Component[P]) CacheVary(vary func(r *http.Request) string) *Component[P] {
+	c.cacheVary = vary
+	return c
+}
+
+// SetCache is called by the registry during component registration to
+// install its RenderCache.
+//
+// User code should not call this directly.
+func (c *Component[P]) SetCache(cache *RenderCache) {
+	c.cache = cache
+}
+
+// ServeCached writes the cached render of props as the response and reports
+// whether there was one. Generated dispatch calls it for render requests
+// after decoding props, with authorized false, and again after Hydrate and
+// the policy check, with authorized true. Components without Require
+// policies are served from the first call, skipping Hydrate; those with
+// policies only from the second.
+func (c *Component[P]) ServeCached(w http.ResponseWriter, r *http.Request, props P, authorized bool) bool {
+	if authorized != (len(c.policies) > 0) {
+		return false
+	}
+	key, ok := c.cacheKey(r, props)
+	if !ok {
+		return false
+	}
+	body, ok := c.cache.get(key)
+	if !ok {
+		return false
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if c.etags && NotModified(w, r, ETag(body)) {
+		return true
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.Write(body)
+	return true
+}
+
+// CacheRender returns tmpl, made to store its output as the render of props
+// when it renders successfully. Generated dispatch calls it for render
+// requests.
+func (c *Component[P]) CacheRender(r *http.Request, props P, tmpl templ.Component) templ.Component {
+	key, ok := c.cacheKey(r, props)
+	if !ok {
+		return tmpl
+	}
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		var buf bytes.Buffer
+		if err := tmpl.Render(ctx, &buf); err != nil {
+			// Streaming components have already started their output
+			w.Write(buf.Bytes())
+			return err
+		}
+		c.cache.set(key, bytes.Clone(buf.Bytes()), c.cacheTTL, c.cacheTags)
+		_, err := w.Write(buf.Bytes())
+		return err
+	})
+}
+
+// InvalidateCache evicts the renders cached with any of tags from the
+// registry's cache. Generated dispatch calls it with Result.GetInvalidate
+// after successful actions.
+func (c *Component[P]) InvalidateCache(tags ...string) {
+	if c.cache != nil && len(tags) > 0 {
+		c.cache.Invalidate(tags...)
+	}
+}
+
+// cacheKey returns the cache key of props for a GET request r, and false if
+// the render isn't cached.
+func (c *Component[P]) cacheKey(r *http.Request, props P) (string, bool) {
+	if c.cache == nil || c.cacheTTL <= 0 || r.Method != http.MethodGet {
+		return "", false
+	}
+	canonical, ok := canonicalProps(props)
+	if !ok {
+		return "", false
+	}
+	vary := ""
+	if c.cacheVary != nil {
+		vary = c.cacheVary(r)
+	}
+	return c.prefix + "\x00" + vary + "\x00" + canonical, true
+}
+
+// canonicalProps returns a digest of props that is equal for equal props.
+func canonicalProps(props any) (string, bool) {
+	e, ok := props.(Encodable)
+	if !ok {
+		return "", false
+	}
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetSortMapKeys(true)
+	if err := enc.Encode(e.HXEncode()); err != nil {
+		return "", false
+	}
+	sum := sha256.Sum256(buf.Bytes())
+	return hex.EncodeToString(sum[:]), true
+}
@@ -0,0 +1,123 @@
+package hxcmp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/a-h/templ"
+)
+
+func TestRenderCache(t *testing.T) {
+	c := NewRenderCache(2)
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	c.set("a", []byte("A"), time.Minute, []string{"x"})
+	c.set("b", []byte("B"), time.Minute, []string{"x", "y"})
+	if body, ok := c.get("a"); !ok || string(body) != "A" {
+		t.Fatalf("get(a) = %q, %v", body, ok)
+	}
+
+	// "b" is now the least recently used
+	c.set("c", []byte("C"), time.Second, []string{"y"})
+	if _, ok := c.get("b"); ok {
+		t.Error("get(b) found an evicted entry")
+	}
+	if c.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", c.Len())
+	}
+
+	now = now.Add(time.Second)
+	if _, ok := c.get("c"); ok {
+		t.Error("get(c) found an expired entry")
+	}
+
+	c.set("c", []byte("C"), time.Minute, []string{"y"})
+	if n := c.Invalidate("x", "z"); n != 1 {
+		t.Errorf("Invalidate() = %d, want 1", n)
+	}
+	if _, ok := c.get("a"); ok {
+		t.Error("get(a) found an invalidated entry")
+	}
+	if _, ok := c.get("c"); !ok {
+		t.Error("get(c) lost an entry with other tags")
+	}
+
+	c.SetMaxEntries(0)
+	if c.Len() != 0 || len(c.tags) != 0 {
+		t.Errorf("after SetMaxEntries(0): Len() = %d, tags = %v", c.Len(), c.tags)
+	}
+}
+
+func TestComponentCache(t *testing.T) {
+	c := New[widgetProps]("widget")
+	c.SetCache(NewRenderCache(DefaultCacheEntries))
+	c.SetAuthorizer(func(ctx context.Context, req AuthRequest) error { return nil })
+	get := func(vary string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, c.Prefix()+"/", nil)
+		r.Header.Set("X-User", vary)
+		return r
+	}
+	render := func(r *http.Request, props widgetProps, html string) {
+		rec := httptest.NewRecorder()
+		if err := c.WriteRender(rec, r, "", 0, c.CacheRender(r, props, templ.Raw(html))); err != nil {
+			t.Fatalf("WriteRender() error = %v", err)
+		}
+	}
+
+	// Without Cache, nothing is stored
+	render(get(""), widgetProps{ID: "a"}, "<p>a</p>")
+	if c.ServeCached(httptest.NewRecorder(), get(""), widgetProps{ID: "a"}, false) {
+		t.Fatal("ServeCached() = true for an uncached component")
+	}
+
+	c.Cache(time.Minute, "widgets").CacheVary(func(r *http.Request) string { return r.Header.Get("X-User") })
+	render(get("alice"), widgetProps{ID: "a"}, "<p>a</p>")
+
+	rec := httptest.NewRecorder()
+	if !c.ServeCached(rec, get("alice"), widgetProps{ID: "a"}, false) {
+		t.Fatal("ServeCached() = false after a render")
+	}
+	if rec.Body.String() != "<p>a</p>" || rec.Header().Get("Content-Length") != "8" {
+		t.Errorf("cached response = %v %q", rec.Header(), rec.Body.String())
+	}
+	for name, hit := range map[string]bool{
+		"other props": c.ServeCached(httptest.NewRecorder(), get("alice"), widgetProps{ID: "b"}, false),
+		"other vary":  c.ServeCached(httptest.NewRecorder(), get("bob"), widgetProps{ID: "a"}, false),
+		"post":        c.ServeCached(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", nil), widgetProps{ID: "a"}, false),
+	} {
+		if hit {
+			t.Errorf("%s: ServeCached() = true", name)
+		}
+	}
+
+	// Failed renders are not stored
+	c.WriteRender(httptest.NewRecorder(), get("carol"), "", 0, c.CacheRender(get("carol"), widgetProps{ID: "a"}, failingTemplate))
+	if c.ServeCached(httptest.NewRecorder(), get("carol"), widgetProps{ID: "a"}, false) {
+		t.Error("ServeCached() = true after a failed render")
+	}
+
+	c.InvalidateCache("widgets")
+	if c.ServeCached(httptest.NewRecorder(), get("alice"), widgetProps{ID: "a"}, false) {
+		t.Error("ServeCached() = true after InvalidateCache")
+	}
+}
+
+func TestComponentCachePolicies(t *testing.T) {
+	c := New[widgetProps]("widget").Cache(time.Minute).Require("view")
+	c.SetCache(NewRenderCache(DefaultCacheEntries))
+	r := httptest.NewRequest(http.MethodGet, c.Prefix()+"/", nil)
+	c.WriteRender(httptest.NewRecorder(), r, "", 0, c.CacheRender(r, widgetProps{ID: "a"}, templ.Raw("<p>a</p>")))
+
+	// Policies need hydrated props, so hits are only served once checked
+	rec := httptest.NewRecorder()
+	if c.ServeCached(rec, r, widgetProps{ID: "a"}, false) || rec.Body.Len() != 0 {
+		t.Errorf("ServeCached() served before the policy check: %q", rec.Body.String())
+	}
+	if !c.ServeCached(rec, r, widgetProps{ID: "a"}, true) || rec.Body.String() != "<p>a</p>" {
+		t.Errorf("ServeCached() after the policy check = %q", rec.Body.String())
+	}
+}
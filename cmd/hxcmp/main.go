@@ -2,8 +2,15 @@ package main
 
 import (
 	"fmt"
+	"log"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/pthm/hxcmp/lib/analyzer"
 	"github.com/pthm/hxcmp/lib/generator"
 )
 
@@ -18,12 +25,33 @@ func main() {
 	cmd := os.Args[1]
 	args := os.Args[2:]
 
+	if isVetTool(os.Args[1:]) {
+		runVet(os.Args[1:])
+	}
+
 	switch cmd {
 	case "generate":
 		if err := runGenerate(args); err != nil {
 			fmt.Fprintf(os.Stderr, "error: %v\n", err)
 			os.Exit(1)
 		}
+	case "new":
+		if err := runNew(args); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+	case "manifest":
+		if err := runManifest(args); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+	case "graph":
+		if err := runGraph(args); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+	case "vet":
+		runVet(args)
 	case "clean":
 		if err := runClean(args); err != nil {
 			fmt.Fprintf(os.Stderr, "error: %v\n", err)
@@ -48,28 +76,86 @@ Usage:
 
 Commands:
   generate [packages]   Generate code for components (e.g., ./... or ./components/...)
-  clean [packages]      Remove generated files (*_hx.go)
+  new <pkg>/<name>      Scaffold a new component and run generation
+  manifest [packages]   Describe components, props and actions
+  graph [packages]      Graph components, actions and the events between them
+  vet [packages]        Check components for mistakes the compiler can't catch
+  clean [packages]      Remove generated files (*_hx.go, *_hx_test.go, *_hx_ext.go)
   version               Print version
   help                  Show this help
 
 Options for generate:
   --dry-run             Show what would be generated without writing files
+  --force               Regenerate packages whose inputs are unchanged
+  --json                Print a JSON report of packages, components, files and
+                        diagnostics to stdout (progress goes to stderr)
+  --workers <n>         Packages to generate concurrently (default: GOMAXPROCS)
+  --template <file>     Extra template rendered per component into *_hx_ext.go
+                        (repeatable)
+
+Options for new:
+  --actions <list>      Actions to register, e.g. edit,delete:DELETE
+  --sensitive           Encrypt props instead of signing them
+  --dry-run             Show what would be created without writing files
+
+Options for manifest:
+  --format <format>     Output format: json (default) or markdown
+
+Options for graph:
+  --format <format>     Output format: dot (default), mermaid or json
+                        Orphan events and listeners are reported on stderr
+
+Options for vet:
+  Run 'hxcmp vet -help' for the analyzer's flags. hxcmp also works as a vet
+  tool: go vet -vettool=$(which hxcmp) ./...
 
 Examples:
   hxcmp generate ./...                    Generate for all packages
   hxcmp generate ./components/fileviewer  Generate for specific package
   hxcmp generate --dry-run ./...          Preview generation
+  hxcmp new ./components/taskdetail --actions edit,delete:DELETE
+  hxcmp manifest --format markdown ./...  Document all components
+  hxcmp graph ./... | dot -Tsvg > g.svg   Render the component graph
+  hxcmp vet ./...                         Check all components
   hxcmp clean ./...                       Remove all generated files`)
 }
 
 func runGenerate(args []string) error {
-	var dryRun bool
+	var dryRun, force, jsonOut bool
+	var workers int
+	var templates []string
 	var patterns []string
 
-	for _, arg := range args {
-		if arg == "--dry-run" {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--dry-run":
 			dryRun = true
-		} else {
+		case arg == "--force":
+			force = true
+		case arg == "--json":
+			jsonOut = true
+		case arg == "--workers":
+			if i+1 >= len(args) {
+				return fmt.Errorf("--workers requires a value")
+			}
+			i++
+			n, err := strconv.Atoi(args[i])
+			if err != nil || n < 1 {
+				return fmt.Errorf("--workers must be a positive integer")
+			}
+			workers = n
+		case arg == "--template":
+			if i+1 >= len(args) {
+				return fmt.Errorf("--template requires a value")
+			}
+			i++
+			templates = append(templates, args[i])
+		case strings.HasPrefix(arg, "--template="):
+			templates = append(templates, strings.TrimPrefix(arg, "--template="))
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown option: %s", arg)
+		default:
 			patterns = append(patterns, arg)
 		}
 	}
@@ -78,11 +164,191 @@ func runGenerate(args []string) error {
 		patterns = []string{"./..."}
 	}
 
+	opts := generator.Options{
+		DryRun:    dryRun,
+		Force:     force,
+		Workers:   workers,
+		Templates: templates,
+	}
+	if !jsonOut {
+		return generator.New(opts).Generate(patterns...)
+	}
+
+	// Keep stdout for the report
+	opts.Logger = log.New(os.Stderr, "", 0)
+	report, err := generator.New(opts).GenerateReport(patterns...)
+	if writeErr := report.WriteJSON(os.Stdout); writeErr != nil {
+		return writeErr
+	}
+	return err
+}
+
+func runNew(args []string) error {
+	var dryRun, sensitive bool
+	var actionSpec string
+	var target string
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--dry-run":
+			dryRun = true
+		case arg == "--sensitive":
+			sensitive = true
+		case arg == "--actions":
+			if i+1 >= len(args) {
+				return fmt.Errorf("--actions requires a value")
+			}
+			i++
+			actionSpec = args[i]
+		case strings.HasPrefix(arg, "--actions="):
+			actionSpec = strings.TrimPrefix(arg, "--actions=")
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown option: %s", arg)
+		case target == "":
+			target = arg
+		default:
+			return fmt.Errorf("unexpected argument: %s", arg)
+		}
+	}
+
+	if target == "" {
+		return fmt.Errorf("usage: hxcmp new <pkg>/<name> [--actions list] [--sensitive]")
+	}
+
+	actions, err := generator.ParseScaffoldActions(actionSpec)
+	if err != nil {
+		return err
+	}
+
 	gen := generator.New(generator.Options{
 		DryRun: dryRun,
 	})
 
-	return gen.Generate(patterns...)
+	err = gen.Scaffold(generator.ScaffoldOptions{
+		Dir:       filepath.Dir(target),
+		Name:      filepath.Base(target),
+		Actions:   actions,
+		Sensitive: sensitive,
+	})
+	if err != nil {
+		return err
+	}
+
+	if !dryRun {
+		fmt.Println("run 'templ generate' to compile the new template")
+	}
+	return nil
+}
+
+func runManifest(args []string) error {
+	format := "json"
+	var patterns []string
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--format":
+			if i+1 >= len(args) {
+				return fmt.Errorf("--format requires a value")
+			}
+			i++
+			format = args[i]
+		case strings.HasPrefix(arg, "--format="):
+			format = strings.TrimPrefix(arg, "--format=")
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown option: %s", arg)
+		default:
+			patterns = append(patterns, arg)
+		}
+	}
+
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	gen := generator.New(generator.Options{})
+	manifest, err := gen.Manifest(patterns...)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "json":
+		return manifest.WriteJSON(os.Stdout)
+	case "markdown", "md":
+		return manifest.WriteMarkdown(os.Stdout)
+	default:
+		return fmt.Errorf("unknown format %q (want json or markdown)", format)
+	}
+}
+
+func runGraph(args []string) error {
+	format := "dot"
+	var patterns []string
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--format":
+			if i+1 >= len(args) {
+				return fmt.Errorf("--format requires a value")
+			}
+			i++
+			format = args[i]
+		case strings.HasPrefix(arg, "--format="):
+			format = strings.TrimPrefix(arg, "--format=")
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("unknown option: %s", arg)
+		default:
+			patterns = append(patterns, arg)
+		}
+	}
+
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	gen := generator.New(generator.Options{})
+	graph, err := gen.Graph(patterns...)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "dot":
+		err = graph.WriteDOT(os.Stdout)
+	case "mermaid":
+		err = graph.WriteMermaid(os.Stdout)
+	case "json":
+		err = graph.WriteJSON(os.Stdout)
+	default:
+		return fmt.Errorf("unknown format %q (want dot, mermaid or json)", format)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Orphans go to stderr so the graph can be piped to a renderer
+	for _, ev := range graph.OrphanEvents() {
+		fmt.Fprintf(os.Stderr, "warning: event %q is emitted by %s but nothing listens to it\n", ev.Name, graphPoints(graph, ev.Emitters))
+	}
+	for _, ev := range graph.OrphanListeners() {
+		fmt.Fprintf(os.Stderr, "warning: event %q is listened to by %s but nothing emits it\n", ev.Name, graphPoints(graph, ev.Listeners))
+	}
+	return nil
+}
+
+// graphPoints formats graph points as "components.TodoItem.toggle, main/layout.templ".
+func graphPoints(graph *generator.Graph, points []generator.GraphPoint) string {
+	names := make([]string, len(points))
+	for i, p := range points {
+		names[i] = graph.Label(p.Node)
+		if p.Action != "" {
+			names[i] += "." + p.Action
+		}
+	}
+	return strings.Join(names, ", ")
 }
 
 func runClean(args []string) error {
@@ -94,3 +360,20 @@ func runClean(args []string) error {
 	gen := generator.New(generator.Options{})
 	return gen.Clean(patterns...)
 }
+
+// isVetTool reports whether hxcmp was started by 'go vet -vettool', which
+// queries the tool with -V=full and -flags, then runs it once per package
+// with a *.cfg file.
+func isVetTool(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	first, last := args[0], args[len(args)-1]
+	return strings.HasPrefix(first, "-V=") || first == "-flags" || strings.HasSuffix(last, ".cfg")
+}
+
+// runVet runs the hxcmp analyzer on the packages in args and exits.
+func runVet(args []string) {
+	os.Args = append([]string{os.Args[0]}, args...)
+	singlechecker.Main(analyzer.Analyzer)
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"path/filepath"
 	"runtime"
+	"time"
 
 	"github.com/a-h/templ"
 )
@@ -16,9 +17,12 @@ import (
 // actionDef holds metadata about a registered action.
 // Stored in Component.actions map for lookup by generated dispatch code.
 type actionDef struct {
-	name    string
-	method  string
-	handler any
+	name          string
+	method        string
+	handler       any
+	middleware    []func(http.Handler) http.Handler // Added with ActionBuilder.Use
+	policies      []string                          // Added with ActionBuilder.Require
+	disableDenied bool                              // Set with ActionBuilder.DisableWhenDenied
 }
 
 // ErrorHandler is the function signature for centralized error handling.
@@ -52,13 +56,25 @@ type ErrorHandler func(http.ResponseWriter, *http.Request, error)
 // name and source location (file:line), ensuring uniqueness without manual
 // coordination.
 type Component[P any] struct {
-	name      string
-	prefix    string
-	sensitive bool
-	actions   map[string]*actionDef
-	encoder   *Encoder
-	parent    any          // The concrete component that embeds this
-	onError   ErrorHandler // Centralized error handler from registry
+	name       string
+	prefix     string
+	sensitive  bool
+	streaming  bool // Set with Streaming
+	etags      bool // Set with ETags
+	actions    map[string]*actionDef
+	emits      []string                          // Declared with Emits, sorted
+	listens    []string                          // Declared with Listens, sorted
+	middleware []func(http.Handler) http.Handler // Added with Use
+	policies   []string                          // Added with Require
+	cacheTTL   time.Duration                     // Set with Cache
+	cacheTags  []string                          // Set with Cache
+	cacheVary  func(*http.Request) string        // Set with CacheVary
+	encoder    *Encoder
+	parent     any          // The concrete component that embeds this
+	onError    ErrorHandler // Centralized error handler from registry
+	authorize  Authorizer   // Policy check from registry
+	observer   Observer     // Lifecycle observer from registry
+	cache      *RenderCache // Render cache from registry
 }
 
 // New creates a new component with the given name.
@@ -122,6 +138,14 @@ func (c *Component[P]) IsSensitive() bool {
 //   - func(ctx, P) Result[P]
 //   - func(ctx, P, *http.Request) Result[P]
 //   - func(ctx, P, http.ResponseWriter) Result[P]
+//   - func(ctx, P, http.ResponseWriter, *http.Request) Result[P]
+//   - func(ctx, P, F) Result[P], where F is a form struct in the same package
+//
+// The handler can be a method of the component (c.handleEdit), a function
+// literal, a package-level function, or a method value on another receiver
+// (c.store.HandleEdit). When the generator can't see the handler's
+// declaration, as for functions from other packages, its signature is
+// checked when the action is served and form structs are not supported.
 //
 // The framework calls Hydrate before invoking the handler and Render
 // after the handler returns OK or Err results.
@@ -134,6 +158,16 @@ func (c *Component[P]) Action(name string, handler any) *ActionBuilder {
 	return &ActionBuilder{action: c.actions[name]}
 }
 
+// Handler returns the handler registered for an action, or nil.
+// Generated dispatch uses it to call handlers that are not methods of the
+// component, such as function literals.
+func (c *Component[P]) Handler(action string) any {
+	if a, ok := c.actions[action]; ok {
+		return a.handler
+	}
+	return nil
+}
+
 // Actions returns the registered actions (used by registry).
 func (c *Component[P]) Actions() map[string]*actionDef {
 	return c.actions
@@ -184,11 +218,7 @@ func (c *Component[P]) OnError() ErrorHandler {
 // Uses HTMX's "intersect once" trigger - loads once when entering viewport.
 func (c *Component[P]) Lazy(props P, placeholder templ.Component) templ.Component {
 	path, encoded := c.buildActionURL("", props)
-	url := path
-	if encoded != "" {
-		url = path + "?p=" + encoded
-	}
-	return lazyComponent(url, placeholder, "intersect once")
+	return lazyComponent(ActionURL(path, encoded), placeholder, "intersect once")
 }
 
 // Defer returns a templ component that loads after page load (not on intersection).
@@ -201,11 +231,7 @@ func (c *Component[P]) Lazy(props P, placeholder templ.Component) templ.Componen
 // Uses HTMX's "load" trigger - fires once after page load completes.
 func (c *Component[P]) Defer(props P, placeholder templ.Component) templ.Component {
 	path, encoded := c.buildActionURL("", props)
-	url := path
-	if encoded != "" {
-		url = path + "?p=" + encoded
-	}
-	return lazyComponent(url, placeholder, "load")
+	return lazyComponent(ActionURL(path, encoded), placeholder, "load")
 }
 
 // buildActionURL constructs the path and encoded props for an action.
@@ -0,0 +1,98 @@
+package hxcmp
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// PropsChecker collects constraint violations while props are decoded.
+//
+// Generated HXDecode methods use it to enforce the constraints declared in
+// hx tags:
+//
+//	type Props struct {
+//	    ID     string `hx:"id,required"`
+//	    Page   int    `hx:"page,default=1,min=1,max=100"`
+//	    Status string `hx:"status,oneof=open|closed"`
+//	}
+//
+// User code should not need it directly.
+type PropsChecker struct {
+	fields []FieldError
+}
+
+// Fail records a violation for the field with the given props key.
+func (c *PropsChecker) Fail(key string, value any, err error) {
+	c.fields = append(c.fields, FieldError{Field: key, Value: formatValue(value), Err: err})
+}
+
+// Required records a violation unless ok. Generated code sets ok when a
+// string or time.Time field decoded to a non-zero value, or when a numeric or
+// bool field's key is present, so 0 and false satisfy required. raw is the
+// encoded value, if any.
+func (c *PropsChecker) Required(key string, raw any, ok bool) {
+	if !ok {
+		c.Fail(key, raw, errors.New("is required"))
+	}
+}
+
+// MinLen records a violation if v has fewer than n characters.
+func (c *PropsChecker) MinLen(key, v string, n int) {
+	if utf8.RuneCountInString(v) < n {
+		c.Fail(key, v, fmt.Errorf("must be at least %d characters", n))
+	}
+}
+
+// MaxLen records a violation if v has more than n characters.
+func (c *PropsChecker) MaxLen(key, v string, n int) {
+	if utf8.RuneCountInString(v) > n {
+		c.Fail(key, v, fmt.Errorf("must be at most %d characters", n))
+	}
+}
+
+// Err returns a *PropsError describing every violation, or nil.
+func (c *PropsChecker) Err() error {
+	if len(c.fields) == 0 {
+		return nil
+	}
+	return &PropsError{Fields: c.fields}
+}
+
+// CheckMin records a violation if v is less than min.
+func CheckMin[T cmp.Ordered](c *PropsChecker, key string, v, min T) {
+	if v < min {
+		c.Fail(key, v, fmt.Errorf("must be at least %v", min))
+	}
+}
+
+// CheckMax records a violation if v is greater than max.
+func CheckMax[T cmp.Ordered](c *PropsChecker, key string, v, max T) {
+	if v > max {
+		c.Fail(key, v, fmt.Errorf("must be at most %v", max))
+	}
+}
+
+// CheckOneOf records a violation if v is not one of allowed.
+func CheckOneOf[T comparable](c *PropsChecker, key string, v T, allowed ...T) {
+	for _, a := range allowed {
+		if v == a {
+			return
+		}
+	}
+	names := make([]string, len(allowed))
+	for i, a := range allowed {
+		names[i] = fmt.Sprint(a)
+	}
+	c.Fail(key, v, fmt.Errorf("must be one of %s", strings.Join(names, ", ")))
+}
+
+// formatValue formats a field value for FieldError.Value.
+func formatValue(v any) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,55 @@
+package hxcmp
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPropsChecker(t *testing.T) {
+	var c PropsChecker
+	if err := c.Err(); err != nil {
+		t.Fatalf("Err() with no violations = %v, want nil", err)
+	}
+
+	c.Required("id", nil, false)
+	c.Required("name", "x", true)
+	c.MinLen("title", "héllo", 5)
+	c.MaxLen("title", "héllo", 4)
+	CheckMin(&c, "page", 0, 1)
+	CheckMax(&c, "page", 3, 10)
+	CheckMax(&c, "ratio", 1.5, 1.0)
+	CheckOneOf(&c, "status", "open", "open", "closed")
+	CheckOneOf(&c, "size", 3, 1, 2)
+
+	err := c.Err()
+	if !IsInvalidProps(err) || !IsValidationError(err) {
+		t.Fatalf("Err() = %v, want it to wrap ErrInvalidProps and ErrValidation", err)
+	}
+
+	var perr *PropsError
+	if !errors.As(err, &perr) {
+		t.Fatalf("Err() = %T, want *PropsError", err)
+	}
+	want := []FieldError{
+		{Field: "id", Value: ""},
+		{Field: "title", Value: "héllo"},
+		{Field: "page", Value: "0"},
+		{Field: "ratio", Value: "1.5"},
+		{Field: "size", Value: "3"},
+	}
+	if len(perr.Fields) != len(want) {
+		t.Fatalf("Fields = %+v, want %d violations", perr.Fields, len(want))
+	}
+	for i, fe := range perr.Fields {
+		if fe.Field != want[i].Field || fe.Value != want[i].Value {
+			t.Errorf("Fields[%d] = %s=%q, want %s=%q", i, fe.Field, fe.Value, want[i].Field, want[i].Value)
+		}
+	}
+
+	if got, want := perr.Fields[4].Error(), "size: must be one of 1, 2"; got != want {
+		t.Errorf("oneof error = %q, want %q", got, want)
+	}
+	if got, want := err.Error(), "hxcmp: invalid props: id: is required; title: must be at most 4 characters; page: must be at least 1; ratio: must be at most 1; size: must be one of 1, 2"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
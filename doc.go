@@ -80,6 +80,8 @@
 // Run 'hxcmp generate' to produce:
 //   - Fast encoder/decoder for Props (implements Encodable/Decodable)
 //   - Wire methods (e.g., WireEdit, WireDelete) returning templ.Attributes
+//   - URL builders (e.g., URLEdit, AbsoluteURLEdit) returning plain URLs
+//   - Form binding for handlers that take a form input struct
 //   - HXServeHTTP dispatcher that routes requests to handlers
 //
 // Generated code eliminates reflection in the hot path and enables
@@ -3,8 +3,10 @@ package hxcmp
 import (
 	"context"
 	"errors"
+	"fmt"
 	"html"
 	"io"
+	"strings"
 
 	"github.com/a-h/templ"
 )
@@ -34,8 +36,215 @@ var (
 	// handler errors (business logic failures). User code should return raw
 	// errors from Hydrate - the framework handles wrapping automatically.
 	ErrHydrationFailed = errors.New("hxcmp: hydration failed")
+
+	// ErrValidation indicates request input failed validation.
+	//
+	// Generated form binding returns a *ValidationError wrapping this sentinel
+	// when a submitted value cannot be converted to its field type. The default
+	// OnError handler responds with 400 Bad Request.
+	ErrValidation = errors.New("hxcmp: validation failed")
+
+	// ErrInvalidProps indicates decoded props violate their hx tag constraints.
+	//
+	// Generated HXDecode methods return a *PropsError wrapping this sentinel
+	// when a field is missing, out of range, or not one of its allowed values,
+	// so bad props are rejected before Hydrate runs. PropsError also wraps
+	// ErrValidation, so the default OnError handler responds with 400.
+	ErrInvalidProps = errors.New("hxcmp: invalid props")
+
+	// ErrHandlerType indicates an action handler does not have a supported
+	// signature for its component's props type.
+	//
+	// Generated dispatch returns it for handlers whose declaration the
+	// generator couldn't see, such as functions from other packages, since
+	// their signature is only known when the action is served.
+	ErrHandlerType = errors.New("hxcmp: unsupported handler type")
+
+	// ErrPanic indicates component dispatch panicked.
+	//
+	// The registry recovers panics in middleware, Hydrate, handlers and
+	// Render and passes a *PanicError wrapping this sentinel to OnError, so
+	// panics are reported and answered like any other error.
+	ErrPanic = errors.New("hxcmp: panic")
+
+	// ErrForbidden indicates the user may not render a component or run an
+	// action.
+	//
+	// Authorizers return it, or an error wrapping it, to deny a policy named
+	// with Require. The default OnError handler responds with 403 Forbidden.
+	ErrForbidden = errors.New("hxcmp: forbidden")
+
+	// ErrRenderFailed indicates a component's templ output failed to render.
+	//
+	// Output is rendered into a buffer before it is written, so a failing
+	// template is passed to OnError wrapped with this sentinel instead of
+	// sending a truncated fragment. The default handler responds with 500.
+	ErrRenderFailed = errors.New("hxcmp: render failed")
 )
 
+// HandlerTypeError reports that the handler registered for action does not
+// have a supported signature. It wraps ErrHandlerType.
+func HandlerTypeError(action string, handler any) error {
+	return fmt.Errorf("%w: action %q has handler of type %T", ErrHandlerType, action, handler)
+}
+
+// PanicError describes a panic recovered while serving a component request.
+// It wraps ErrPanic:
+//
+//	var perr *hxcmp.PanicError
+//	if errors.As(err, &perr) {
+//	    log.Printf("%v\n%s", perr, perr.Stack)
+//	}
+type PanicError struct {
+	Value     any    // The value passed to panic
+	Stack     []byte // Stack trace of the panicking goroutine
+	Component string // Component name, as passed to hxcmp.New
+	Action    string // Action name; empty for the render endpoint
+}
+
+// Error implements the error interface.
+func (e *PanicError) Error() string {
+	where := e.Component
+	if e.Action != "" {
+		where += "." + e.Action
+	}
+	return fmt.Sprintf("%s in %s: %v", ErrPanic.Error(), where, e.Value)
+}
+
+// Unwrap returns ErrPanic so errors.Is matches the sentinel, and the panic
+// value too when it is an error.
+func (e *PanicError) Unwrap() []error {
+	if err, ok := e.Value.(error); ok {
+		return []error{ErrPanic, err}
+	}
+	return []error{ErrPanic}
+}
+
+// WrapHydrateError wraps an error returned by Hydrate with
+// ErrHydrationFailed, keeping the original error in the chain so IsNotFound
+// and errors.Is still match it. Generated dispatch calls it.
+func WrapHydrateError(err error) error {
+	if err == nil || errors.Is(err, ErrHydrationFailed) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ErrHydrationFailed, err)
+}
+
+// IsHydrationError checks if err was returned by a component's Hydrate.
+func IsHydrationError(err error) bool {
+	return errors.Is(err, ErrHydrationFailed)
+}
+
+// WrapRenderError wraps an error returned by rendering a templ.Component
+// with ErrRenderFailed.
+func WrapRenderError(err error) error {
+	if err == nil || errors.Is(err, ErrRenderFailed) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ErrRenderFailed, err)
+}
+
+// IsRenderError checks if err reports a failed render.
+func IsRenderError(err error) bool {
+	return errors.Is(err, ErrRenderFailed)
+}
+
+// IsForbidden checks if err is an authorization denial.
+func IsForbidden(err error) bool {
+	return errors.Is(err, ErrForbidden)
+}
+
+// IsPanic checks if err reports a recovered panic.
+func IsPanic(err error) bool {
+	return errors.Is(err, ErrPanic)
+}
+
+// FieldError describes a single invalid input field.
+type FieldError struct {
+	Field string // Form key or props key
+	Value string // The submitted value
+	Err   error  // The underlying parse or constraint error
+}
+
+// Error implements the error interface.
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Field, e.Err)
+}
+
+// ValidationError collects the field errors found while binding input.
+//
+// It wraps ErrValidation, so OnError handlers can detect it with
+// IsValidationError and inspect individual fields with errors.As:
+//
+//	var verr *hxcmp.ValidationError
+//	if errors.As(err, &verr) {
+//	    for _, fe := range verr.Fields {
+//	        log.Printf("invalid %s: %v", fe.Field, fe.Err)
+//	    }
+//	}
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Error()
+	}
+	return ErrValidation.Error() + ": " + strings.Join(msgs, "; ")
+}
+
+// Unwrap returns ErrValidation so errors.Is matches the sentinel.
+func (e *ValidationError) Unwrap() error {
+	return ErrValidation
+}
+
+// PropsError collects the props fields that violate their hx tag
+// constraints. It wraps both ErrInvalidProps and ErrValidation:
+//
+//	var perr *hxcmp.PropsError
+//	if errors.As(err, &perr) {
+//	    for _, fe := range perr.Fields {
+//	        log.Printf("bad prop %s: %v", fe.Field, fe.Err)
+//	    }
+//	}
+type PropsError struct {
+	Fields []FieldError
+}
+
+// Error implements the error interface.
+func (e *PropsError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Error()
+	}
+	return ErrInvalidProps.Error() + ": " + strings.Join(msgs, "; ")
+}
+
+// Unwrap returns ErrInvalidProps and ErrValidation so errors.Is matches
+// either sentinel.
+func (e *PropsError) Unwrap() []error {
+	return []error{ErrInvalidProps, ErrValidation}
+}
+
+// IsInvalidProps checks if err reports props that violate their constraints.
+func IsInvalidProps(err error) bool {
+	return errors.Is(err, ErrInvalidProps)
+}
+
+// IsValidationError checks if err is an input validation error.
+//
+// Use this to detect invalid form input and return 400:
+//
+//	if hxcmp.IsValidationError(err) {
+//	    http.Error(w, "Bad request", http.StatusBadRequest)
+//	    return
+//	}
+func IsValidationError(err error) bool {
+	return errors.Is(err, ErrValidation)
+}
+
 // IsNotFound checks if err is a not-found error.
 //
 // Use this to detect resource not found errors and return 404:
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/pthm/hxcmp/lib/encoding"
@@ -18,6 +19,12 @@ func TestSentinelErrors(t *testing.T) {
 		ErrSignatureInvalid,
 		ErrInvalidFormat,
 		ErrHydrationFailed,
+		ErrValidation,
+		ErrHandlerType,
+		ErrInvalidProps,
+		ErrPanic,
+		ErrForbidden,
+		ErrRenderFailed,
 	}
 
 	for i, err1 := range errs {
@@ -78,6 +85,32 @@ func TestIsDecryptionError(t *testing.T) {
 	}
 }
 
+func TestIsValidationError(t *testing.T) {
+	verr := &ValidationError{Fields: []FieldError{
+		{Field: "priority", Value: "high", Err: errors.New("invalid syntax")},
+	}}
+
+	if !IsValidationError(verr) {
+		t.Error("IsValidationError(*ValidationError) = false, want true")
+	}
+	if !IsValidationError(fmt.Errorf("binding: %w", verr)) {
+		t.Error("IsValidationError(wrapped) = false, want true")
+	}
+	if IsValidationError(ErrDecryptFailed) {
+		t.Error("IsValidationError(ErrDecryptFailed) = true, want false")
+	}
+
+	var target *ValidationError
+	if !errors.As(fmt.Errorf("binding: %w", verr), &target) || target.Fields[0].Field != "priority" {
+		t.Errorf("errors.As did not recover the field errors: %v", target)
+	}
+
+	want := "hxcmp: validation failed: priority: invalid syntax"
+	if got := verr.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
 func TestErrorMessages(t *testing.T) {
 	// Ensure error messages contain "hxcmp:" prefix
 	errs := []error{
@@ -177,3 +210,13 @@ func TestWrapDecodeError(t *testing.T) {
 		})
 	}
 }
+
+func TestHandlerTypeError(t *testing.T) {
+	err := HandlerTypeError("save", func(int) {})
+	if !errors.Is(err, ErrHandlerType) {
+		t.Errorf("HandlerTypeError should wrap ErrHandlerType: %v", err)
+	}
+	if want := `action "save" has handler of type func(int)`; !strings.Contains(err.Error(), want) {
+		t.Errorf("error = %q, want it to contain %q", err.Error(), want)
+	}
+}
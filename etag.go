@@ -0,0 +1,84 @@
+package hxcmp
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// ETags makes the component's render endpoint send an ETag computed from
+// the rendered bytes and answer a matching If-None-Match with 304 Not
+// Modified, so Lazy loads and event-driven refreshes of unchanged content
+// are not resent.
+//
+// The component still renders to compute the ETag. Implement Versioner to
+// skip rendering as well; components that do don't need to call ETags.
+// ETags has no effect on streaming components.
+func (c *Component[P]) ETags() *Component[P] {
+	c.etags = true
+	return c
+}
+
+// UsesETags returns whether the component sends ETags computed from its
+// rendered bytes.
+func (c *Component[P]) UsesETags() bool {
+	return c.etags
+}
+
+// ETag returns a strong entity tag for data.
+func ETag(data []byte) string {
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// NotModified sets etag and the Vary headers HTMX requests need on the
+// response, and reports whether r's If-None-Match matches it. When it does,
+// NotModified writes 304 Not Modified and the caller must not write a body.
+// Generated dispatch calls it for components implementing Versioner.
+func NotModified(w http.ResponseWriter, r *http.Request, etag string) bool {
+	h := w.Header()
+	h.Set("ETag", etag)
+	addVary(h, "HX-Request", "HX-Target")
+	if !etagMatches(r.Header.Get("If-None-Match"), etag) {
+		return false
+	}
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// etagMatches reports whether an If-None-Match header matches etag, using
+// the weak comparison RFC 9110 specifies for it.
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// addVary adds header names to the Vary header, skipping ones already
+// listed.
+func addVary(h http.Header, names ...string) {
+	present := make(map[string]bool)
+	for _, v := range h.Values("Vary") {
+		for _, name := range strings.Split(v, ",") {
+			present[http.CanonicalHeaderKey(strings.TrimSpace(name))] = true
+		}
+	}
+	var missing []string
+	for _, name := range names {
+		if !present[http.CanonicalHeaderKey(name)] {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		h.Add("Vary", strings.Join(missing, ", "))
+	}
+}
@@ -0,0 +1,78 @@
+package hxcmp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+func TestETagMatches(t *testing.T) {
+	etag := ETag([]byte("hello"))
+	tests := []struct {
+		ifNoneMatch string
+		want        bool
+	}{
+		{"", false},
+		{etag, true},
+		{"W/" + etag, true},
+		{`"other", ` + etag, true},
+		{`"other"`, false},
+		{"*", true},
+	}
+	for _, tt := range tests {
+		if got := etagMatches(tt.ifNoneMatch, etag); got != tt.want {
+			t.Errorf("etagMatches(%q) = %v, want %v", tt.ifNoneMatch, got, tt.want)
+		}
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	etag := ETag([]byte("hello"))
+
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Vary", "Accept-Encoding, hx-request")
+	if NotModified(rec, httptest.NewRequest(http.MethodGet, "/", nil), etag) {
+		t.Error("NotModified() = true without If-None-Match")
+	}
+	if got := rec.Header().Values("Vary"); len(got) != 2 || got[1] != "HX-Target" {
+		t.Errorf("Vary = %q, want HX-Target added once", got)
+	}
+	if rec.Header().Get("ETag") != etag {
+		t.Errorf("ETag = %q, want %q", rec.Header().Get("ETag"), etag)
+	}
+
+	rec = httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("If-None-Match", etag)
+	if !NotModified(rec, r, etag) || rec.Code != http.StatusNotModified {
+		t.Errorf("NotModified() with a matching If-None-Match: status = %d", rec.Code)
+	}
+}
+
+func TestWriteRenderETags(t *testing.T) {
+	c := New[widgetProps]("widget").ETags()
+	if !c.UsesETags() {
+		t.Fatal("UsesETags() = false after ETags()")
+	}
+	tmpl := templ.Raw("<p>hello</p>")
+	etag := ETag([]byte("<p>hello</p>"))
+
+	r := httptest.NewRequest(http.MethodGet, c.Prefix()+"/", nil)
+	r.Header.Set("If-None-Match", etag)
+	rec := httptest.NewRecorder()
+	if err := c.WriteRender(rec, r, "", 0, tmpl); err != nil {
+		t.Fatalf("WriteRender() error = %v", err)
+	}
+	if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Errorf("response = %d %q, want 304 without a body", rec.Code, rec.Body.String())
+	}
+
+	// Responses with another status are sent as is
+	rec = httptest.NewRecorder()
+	c.WriteRender(rec, r, "", http.StatusCreated, tmpl)
+	if rec.Code != http.StatusCreated || rec.Header().Get("ETag") != "" {
+		t.Errorf("response = %d %v", rec.Code, rec.Header())
+	}
+}
@@ -0,0 +1,88 @@
+package hxcmp
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/a-h/templ"
+)
+
+// Emits declares an event the component triggers, with an optional payload
+// struct describing the event's detail.
+//
+// Declarations are read by the generator, which emits a constant and a typed
+// helper per event into the package's hx_helpers.go:
+//
+//	c.Emits("todo:changed", TodoChanged{})
+//
+//	// Generated:
+//	const EventTodoChanged = "todo:changed"
+//	func EmitTodoChanged[P any](result hxcmp.Result[P], payload TodoChanged) hxcmp.Result[P]
+//
+//	// In a handler:
+//	return EmitTodoChanged(hxcmp.OK(props), TodoChanged{ID: props.ID})
+//
+// The event name and payload must be literals. At runtime the declaration
+// is only recorded for Registry.Components.
+func (c *Component[P]) Emits(event string, payload ...any) *Component[P] {
+	c.emits = appendEvent(c.emits, event)
+	return c
+}
+
+// Listens declares events the component refreshes on. The generator emits a
+// Listen<Event> helper for each, returning the hx-trigger attribute:
+//
+//	c.Listens("todo:changed")
+//
+//	<div { c.WireRender(props)... } { ListenTodoChanged()... }>
+//
+// Generation fails if nothing in the generated packages emits a listened
+// event, so typos in event names are caught before they reach the browser.
+func (c *Component[P]) Listens(events ...string) *Component[P] {
+	for _, event := range events {
+		c.listens = appendEvent(c.listens, event)
+	}
+	return c
+}
+
+// appendEvent adds event to a sorted list of event names, ignoring
+// duplicates.
+func appendEvent(events []string, event string) []string {
+	i := sort.SearchStrings(events, event)
+	if i < len(events) && events[i] == event {
+		return events
+	}
+	events = append(events, "")
+	copy(events[i+1:], events[i:])
+	events[i] = event
+	return events
+}
+
+// EventDetail converts an event payload to the map sent in the HX-Trigger
+// header, using the payload's JSON encoding. Used by generated Emit helpers;
+// a payload that doesn't encode to a JSON object yields nil.
+func EventDetail(payload any) map[string]any {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil
+	}
+	var detail map[string]any
+	if err := json.Unmarshal(data, &detail); err != nil {
+		return nil
+	}
+	return detail
+}
+
+// Listen returns an hx-trigger attribute that fires when any of the events
+// is triggered on the page body, which is where HX-Trigger events bubble to.
+// Used by generated Listen helpers:
+//
+//	<div hx-get="..." { hxcmp.Listen("todo:changed", "todo:deleted")... }>
+func Listen(events ...string) templ.Attributes {
+	triggers := make([]string, len(events))
+	for i, event := range events {
+		triggers[i] = event + " from:body"
+	}
+	return templ.Attributes{"hx-trigger": strings.Join(triggers, ", ")}
+}
@@ -0,0 +1,54 @@
+package hxcmp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEventDeclarations(t *testing.T) {
+	reg := NewRegistry(make([]byte, 32))
+	c := newWidget("widget")
+	c.Emits("widget:saved", struct{ ID string }{}).Emits("widget:removed").Emits("widget:saved")
+	c.Listens("filter:changed", "clock:tick", "filter:changed")
+	reg.Add(c)
+
+	d := reg.Components()[0]
+	if want := []string{"widget:removed", "widget:saved"}; !reflect.DeepEqual(d.Emits, want) {
+		t.Errorf("Emits = %v, want %v", d.Emits, want)
+	}
+	if want := []string{"clock:tick", "filter:changed"}; !reflect.DeepEqual(d.Listens, want) {
+		t.Errorf("Listens = %v, want %v", d.Listens, want)
+	}
+}
+
+func TestEventDetail(t *testing.T) {
+	type payload struct {
+		ID    string `json:"id"`
+		Count int    `json:"count"`
+	}
+
+	got := EventDetail(payload{ID: "a1", Count: 2})
+	want := map[string]any{"id": "a1", "count": float64(2)}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("EventDetail = %v, want %v", got, want)
+	}
+
+	if got := EventDetail("not an object"); got != nil {
+		t.Errorf("EventDetail(string) = %v, want nil", got)
+	}
+
+	header := BuildTriggerHeader("item:saved", EventDetail(payload{ID: "a1"}))
+	if header != `{"item:saved":{"count":0,"id":"a1"}}` {
+		t.Errorf("trigger header = %s", header)
+	}
+}
+
+func TestListen(t *testing.T) {
+	if got := Listen("todo:changed")["hx-trigger"]; got != "todo:changed from:body" {
+		t.Errorf("Listen = %q", got)
+	}
+	got := Listen("todo:changed", "todo:deleted")["hx-trigger"]
+	if got != "todo:changed from:body, todo:deleted from:body" {
+		t.Errorf("Listen = %q", got)
+	}
+}
@@ -0,0 +1,216 @@
+package hxcmp
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxFormMemory is the in-memory limit for multipart form parsing.
+const maxFormMemory = 32 << 20
+
+// FormBinder reads typed values from a submitted form, collecting conversion
+// failures instead of stopping at the first one.
+//
+// Generated code uses FormBinder to populate form input structs for handlers
+// with the func(ctx, P, F) Result[P] signature:
+//
+//	func (c *TaskDetail) bindTaskForm(r *http.Request) (TaskForm, error) {
+//	    var f TaskForm
+//	    b, err := hxcmp.NewFormBinder(r)
+//	    if err != nil {
+//	        return f, err
+//	    }
+//	    f.Title = b.String("title")
+//	    f.Priority = int(b.Int("priority", 0))
+//	    return f, b.Err()
+//	}
+//
+// Empty and missing values leave the field at its zero value. Values that
+// cannot be parsed are recorded as FieldErrors and reported by Err as a
+// *ValidationError.
+type FormBinder struct {
+	values url.Values
+	errs   []FieldError
+}
+
+// NewFormBinder parses the request form (URL query, urlencoded body, or
+// multipart body) and returns a binder over the combined values.
+func NewFormBinder(r *http.Request) (*FormBinder, error) {
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(maxFormMemory); err != nil {
+			return nil, err
+		}
+	} else if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+	return &FormBinder{values: r.Form}, nil
+}
+
+// NewFormBinderValues returns a binder over already-parsed values.
+func NewFormBinderValues(values url.Values) *FormBinder {
+	return &FormBinder{values: values}
+}
+
+// String returns the first value for key.
+func (b *FormBinder) String(key string) string {
+	return b.values.Get(key)
+}
+
+// Strings returns all values for key.
+func (b *FormBinder) Strings(key string) []string {
+	return b.values[key]
+}
+
+// Int returns the first value for key parsed as a signed integer that fits
+// in bitSize bits (0 means int).
+func (b *FormBinder) Int(key string, bitSize int) int64 {
+	return b.parseInt(key, b.values.Get(key), bitSize)
+}
+
+// Ints returns all values for key parsed as signed integers.
+func (b *FormBinder) Ints(key string, bitSize int) []int64 {
+	var out []int64
+	for _, v := range b.values[key] {
+		out = append(out, b.parseInt(key, v, bitSize))
+	}
+	return out
+}
+
+// Uint returns the first value for key parsed as an unsigned integer that
+// fits in bitSize bits (0 means uint).
+func (b *FormBinder) Uint(key string, bitSize int) uint64 {
+	return b.parseUint(key, b.values.Get(key), bitSize)
+}
+
+// Uints returns all values for key parsed as unsigned integers.
+func (b *FormBinder) Uints(key string, bitSize int) []uint64 {
+	var out []uint64
+	for _, v := range b.values[key] {
+		out = append(out, b.parseUint(key, v, bitSize))
+	}
+	return out
+}
+
+// Float returns the first value for key parsed as a float of bitSize bits.
+func (b *FormBinder) Float(key string, bitSize int) float64 {
+	return b.parseFloat(key, b.values.Get(key), bitSize)
+}
+
+// Floats returns all values for key parsed as floats.
+func (b *FormBinder) Floats(key string, bitSize int) []float64 {
+	var out []float64
+	for _, v := range b.values[key] {
+		out = append(out, b.parseFloat(key, v, bitSize))
+	}
+	return out
+}
+
+// Bool returns the first value for key as a boolean.
+//
+// Checkbox semantics apply: a missing or empty value is false, and "on"
+// (the browser default for checked boxes) is true alongside the values
+// accepted by strconv.ParseBool.
+func (b *FormBinder) Bool(key string) bool {
+	v := b.values.Get(key)
+	if v == "" {
+		return false
+	}
+	if v == "on" {
+		return true
+	}
+	parsed, err := strconv.ParseBool(v)
+	if err != nil {
+		b.fail(key, v, err)
+	}
+	return parsed
+}
+
+// timeLayouts are tried in order when no explicit layout is given. They
+// cover RFC 3339 and the formats produced by datetime-local and date inputs.
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+// Time returns the first value for key parsed as a time.
+//
+// If layout is empty, RFC 3339 and the HTML date and datetime-local input
+// formats are accepted.
+func (b *FormBinder) Time(key, layout string) time.Time {
+	v := b.values.Get(key)
+	if v == "" {
+		return time.Time{}
+	}
+
+	layouts := timeLayouts
+	if layout != "" {
+		layouts = []string{layout}
+	}
+	var err error
+	for _, l := range layouts {
+		var t time.Time
+		if t, err = time.Parse(l, v); err == nil {
+			return t
+		}
+	}
+	b.fail(key, v, err)
+	return time.Time{}
+}
+
+// Err returns a *ValidationError describing every value that failed to
+// parse, or nil if binding succeeded.
+func (b *FormBinder) Err() error {
+	if len(b.errs) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: b.errs}
+}
+
+func (b *FormBinder) parseInt(key, v string, bitSize int) int64 {
+	if v == "" {
+		return 0
+	}
+	n, err := strconv.ParseInt(v, 10, bitSize)
+	if err != nil {
+		b.fail(key, v, err)
+	}
+	return n
+}
+
+func (b *FormBinder) parseUint(key, v string, bitSize int) uint64 {
+	if v == "" {
+		return 0
+	}
+	n, err := strconv.ParseUint(v, 10, bitSize)
+	if err != nil {
+		b.fail(key, v, err)
+	}
+	return n
+}
+
+func (b *FormBinder) parseFloat(key, v string, bitSize int) float64 {
+	if v == "" {
+		return 0
+	}
+	n, err := strconv.ParseFloat(v, bitSize)
+	if err != nil {
+		b.fail(key, v, err)
+	}
+	return n
+}
+
+// fail records a conversion failure, unwrapping strconv.NumError so the
+// message names the problem rather than repeating the input.
+func (b *FormBinder) fail(key, value string, err error) {
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) {
+		err = numErr.Err
+	}
+	b.errs = append(b.errs, FieldError{Field: key, Value: value, Err: err})
+}
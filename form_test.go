@@ -0,0 +1,114 @@
+package hxcmp
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormBinder_Values(t *testing.T) {
+	b := NewFormBinderValues(url.Values{
+		"title":    {"hello"},
+		"tags":     {"a", "b"},
+		"count":    {"42"},
+		"ids":      {"1", "2", "3"},
+		"size":     {"7"},
+		"ratio":    {"0.5"},
+		"done":     {"on"},
+		"archived": {"false"},
+		"due":      {"2024-03-05"},
+		"at":       {"2024-03-05T10:30"},
+		"custom":   {"05/03/2024"},
+	})
+
+	if got := b.String("title"); got != "hello" {
+		t.Errorf("String = %q", got)
+	}
+	if got := b.Strings("tags"); len(got) != 2 || got[1] != "b" {
+		t.Errorf("Strings = %v", got)
+	}
+	if got := b.Int("count", 0); got != 42 {
+		t.Errorf("Int = %d", got)
+	}
+	if got := b.Ints("ids", 64); len(got) != 3 || got[2] != 3 {
+		t.Errorf("Ints = %v", got)
+	}
+	if got := b.Uint("size", 8); got != 7 {
+		t.Errorf("Uint = %d", got)
+	}
+	if got := b.Float("ratio", 64); got != 0.5 {
+		t.Errorf("Float = %v", got)
+	}
+	if !b.Bool("done") {
+		t.Error("Bool(on) = false")
+	}
+	if b.Bool("archived") || b.Bool("missing") {
+		t.Error("Bool(false/missing) = true")
+	}
+	if got := b.Time("due", ""); !got.Equal(time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Time(date) = %v", got)
+	}
+	if got := b.Time("at", ""); !got.Equal(time.Date(2024, 3, 5, 10, 30, 0, 0, time.UTC)) {
+		t.Errorf("Time(datetime-local) = %v", got)
+	}
+	if got := b.Time("custom", "02/01/2006"); !got.Equal(time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Time(layout) = %v", got)
+	}
+	if got := b.Int("missing", 0); got != 0 {
+		t.Errorf("Int(missing) = %d", got)
+	}
+	if err := b.Err(); err != nil {
+		t.Errorf("Err() = %v", err)
+	}
+}
+
+func TestFormBinder_Errors(t *testing.T) {
+	b := NewFormBinderValues(url.Values{
+		"count": {"many"},
+		"small": {"300"},
+		"done":  {"maybe"},
+		"due":   {"tomorrow"},
+	})
+
+	b.Int("count", 0)
+	b.Uint("small", 8)
+	b.Bool("done")
+	b.Time("due", "")
+
+	err := b.Err()
+	if !IsValidationError(err) {
+		t.Fatalf("Err() = %v, want validation error", err)
+	}
+
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Err() is %T, want *ValidationError", err)
+	}
+	var fields []string
+	for _, fe := range verr.Fields {
+		fields = append(fields, fe.Field)
+	}
+	if got := strings.Join(fields, ","); got != "count,small,done,due" {
+		t.Errorf("failed fields = %s", got)
+	}
+	if verr.Fields[1].Value != "300" {
+		t.Errorf("Value = %q, want %q", verr.Fields[1].Value, "300")
+	}
+}
+
+func TestNewFormBinder_Request(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/?page=2", strings.NewReader("title=hi"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	b, err := NewFormBinder(req)
+	if err != nil {
+		t.Fatalf("NewFormBinder() error = %v", err)
+	}
+	if b.String("title") != "hi" || b.Int("page", 0) != 2 {
+		t.Errorf("title = %q, page = %d", b.String("title"), b.Int("page", 0))
+	}
+}
@@ -0,0 +1,59 @@
+package hxcmp
+
+import (
+	"fmt"
+	"sort"
+)
+
+// GeneratedInfo records what 'hxcmp generate' saw when it generated a
+// component's code. The registry compares it with the component's runtime
+// actions to catch code that is out of date.
+type GeneratedInfo struct {
+	SourceHash string            // SHA-256 of the component source file, e.g. "sha256:9f86d0..."
+	Actions    []GeneratedAction // Actions with generated dispatch, sorted by name
+}
+
+// GeneratedAction is an action known to generated code.
+type GeneratedAction struct {
+	Name   string
+	Method string
+}
+
+// Generated is implemented by generated code. Components generated before
+// staleness detection existed don't implement it and are not checked.
+type Generated interface {
+	HXGenerated() GeneratedInfo
+}
+
+// staleActions compares the actions registered on comp at runtime with those
+// its generated code dispatches, and describes every action that would not
+// be served. It returns nil when the generated code is up to date or comp
+// has no generated info.
+func staleActions(comp any) []string {
+	gen, ok := comp.(Generated)
+	if !ok {
+		return nil
+	}
+	d, ok := comp.(describer)
+	if !ok {
+		return nil
+	}
+
+	generated := make(map[string]string)
+	for _, a := range gen.HXGenerated().Actions {
+		generated[a.Name] = a.Method
+	}
+
+	var problems []string
+	for _, a := range d.describe().Actions {
+		method, ok := generated[a.Name]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("action %q has no generated dispatch", a.Name))
+		case method != a.Method:
+			problems = append(problems, fmt.Sprintf("action %q is registered as %s but generated as %s", a.Name, a.Method, method))
+		}
+	}
+	sort.Strings(problems)
+	return problems
+}
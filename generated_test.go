@@ -0,0 +1,115 @@
+package hxcmp
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// generatedWidget adds generated info to the widget test component.
+type generatedWidget struct {
+	*widget
+	info GeneratedInfo
+}
+
+func (c *generatedWidget) HXGenerated() GeneratedInfo {
+	return c.info
+}
+
+func newGeneratedWidget(actions ...GeneratedAction) *generatedWidget {
+	return &generatedWidget{
+		widget: newWidget("widget"),
+		info:   GeneratedInfo{SourceHash: "sha256:test", Actions: actions},
+	}
+}
+
+func TestStaleActions(t *testing.T) {
+	tests := []struct {
+		name    string
+		actions []GeneratedAction
+		want    []string
+	}{
+		{
+			name: "up to date",
+			actions: []GeneratedAction{
+				{Name: "remove", Method: http.MethodDelete},
+				{Name: "save", Method: http.MethodPost},
+			},
+		},
+		{
+			name: "missing action",
+			actions: []GeneratedAction{
+				{Name: "save", Method: http.MethodPost},
+			},
+			want: []string{`action "remove" has no generated dispatch`},
+		},
+		{
+			name: "method changed",
+			actions: []GeneratedAction{
+				{Name: "remove", Method: http.MethodPost},
+				{Name: "save", Method: http.MethodPost},
+			},
+			want: []string{`action "remove" is registered as DELETE but generated as POST`},
+		},
+		{
+			name: "generated action removed from constructor",
+			actions: []GeneratedAction{
+				{Name: "archive", Method: http.MethodPost},
+				{Name: "remove", Method: http.MethodDelete},
+				{Name: "save", Method: http.MethodPost},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := staleActions(newGeneratedWidget(tt.actions...))
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("staleActions() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := staleActions(newWidget("widget")); got != nil {
+		t.Errorf("staleActions() without generated info = %q, want nil", got)
+	}
+}
+
+func TestRegistryAddStaleStrict(t *testing.T) {
+	reg := NewRegistry(make([]byte, 32))
+	reg.Strict = true
+	c := newGeneratedWidget(GeneratedAction{Name: "save", Method: http.MethodPost})
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("expected panic for stale generated code")
+		}
+		if msg := r.(string); !strings.Contains(msg, `action "remove" has no generated dispatch`) {
+			t.Errorf("panic = %q", msg)
+		}
+		if len(reg.Components()) != 0 {
+			t.Error("stale component was registered")
+		}
+	}()
+	reg.Add(c)
+}
+
+func TestRegistryAddStaleLogs(t *testing.T) {
+	var buf bytes.Buffer
+	reg := NewRegistry(make([]byte, 32))
+	reg.Logger = slog.New(slog.NewTextHandler(&buf, nil))
+	reg.Add(newGeneratedWidget(GeneratedAction{Name: "save", Method: http.MethodPost}))
+
+	out := buf.String()
+	for _, want := range []string{"level=WARN", "generated code is out of date", "component=*hxcmp.generatedWidget", `action \"remove\" has no generated dispatch`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log output missing %q: %q", want, out)
+		}
+	}
+	if len(reg.Components()) != 1 {
+		t.Error("component not registered in non-strict mode")
+	}
+}
@@ -5,6 +5,11 @@ go 1.23.0
 require (
 	github.com/a-h/templ v0.3.977
 	github.com/vmihailenco/msgpack/v5 v5.4.1
+	golang.org/x/tools v0.35.0
 )
 
-require github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+require (
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	golang.org/x/mod v0.26.0 // indirect
+	golang.org/x/sync v0.16.0 // indirect
+)
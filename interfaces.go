@@ -49,6 +49,22 @@ type Renderer[P any] interface {
 	Render(ctx context.Context, props P) templ.Component
 }
 
+// Versioner is implemented by components that can tell whether their
+// output changed without rendering it. Version returns a string that
+// changes whenever Render's output for props would, such as an updated-at
+// timestamp or revision number:
+//
+//	func (c *TaskDetail) Version(ctx context.Context, props Props) (string, error) {
+//	    return props.Task.UpdatedAt.Format(time.RFC3339Nano), nil
+//	}
+//
+// The render endpoint of such components sends an ETag derived from the
+// version, with props already hydrated, and answers a matching
+// If-None-Match with 304 Not Modified without calling Render.
+type Versioner[P any] interface {
+	Version(ctx context.Context, props P) (string, error)
+}
+
 // HXComponent is implemented by generated code to enable the registry to
 // dispatch requests without reflection.
 //
@@ -62,3 +78,10 @@ type HXComponent interface {
 	HXPrefix() string
 	HXServeHTTP(w http.ResponseWriter, r *http.Request)
 }
+
+// OOBComponent is implemented by generated code so components can be
+// rendered out of band with Result.OOB. HXRender hydrates props, checks the
+// component's policies and returns its Render output.
+type OOBComponent interface {
+	HXRender(ctx context.Context, props any) (templ.Component, error)
+}
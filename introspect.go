@@ -0,0 +1,160 @@
+package hxcmp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+)
+
+// ComponentDescription describes a registered component.
+//
+// Returned by Registry.Components for startup logging, health endpoints,
+// and assertions in integration tests.
+type ComponentDescription struct {
+	Name      string              `json:"name"`
+	Prefix    string              `json:"prefix"`
+	Type      string              `json:"type"`       // Go type, e.g. "*components.TaskDetail"
+	PropsType string              `json:"props_type"` // e.g. "components.TaskDetailProps"
+	Sensitive bool                `json:"sensitive"`
+	Actions   []ActionDescription `json:"actions"`
+	Emits     []string            `json:"emits,omitempty"`   // Events declared with Emits
+	Listens   []string            `json:"listens,omitempty"` // Events declared with Listens
+}
+
+// ActionDescription describes a registered action.
+type ActionDescription struct {
+	Name   string `json:"name"`
+	Method string `json:"method"`
+	Path   string `json:"path"`
+}
+
+// Route describes a single method and path served by the registry.
+//
+// The default render endpoint is reported with an empty Action.
+type Route struct {
+	Method    string `json:"method"`
+	Path      string `json:"path"`
+	Component string `json:"component"` // Go type of the component
+	Action    string `json:"action,omitempty"`
+}
+
+// describer is implemented by Component[P] and promoted onto every component
+// that embeds it, letting the registry describe components without
+// reflection on unexported fields.
+type describer interface {
+	describe() ComponentDescription
+}
+
+// describe returns the description of the component. The Type field is
+// filled in by the registry, which knows the concrete embedding type.
+func (c *Component[P]) describe() ComponentDescription {
+	desc := ComponentDescription{
+		Name:      c.name,
+		Prefix:    c.prefix,
+		PropsType: reflect.TypeFor[P]().String(),
+		Sensitive: c.sensitive,
+		Actions:   []ActionDescription{},
+		Emits:     c.emits,
+		Listens:   c.listens,
+	}
+	for name, a := range c.actions {
+		method := a.method
+		if method == "" {
+			method = http.MethodPost
+		}
+		desc.Actions = append(desc.Actions, ActionDescription{
+			Name:   name,
+			Method: method,
+			Path:   c.prefix + "/" + name,
+		})
+	}
+	sort.Slice(desc.Actions, func(i, j int) bool {
+		return desc.Actions[i].Name < desc.Actions[j].Name
+	})
+	return desc
+}
+
+// Components returns descriptions of all registered components, sorted by
+// prefix.
+//
+//	for _, c := range reg.Components() {
+//	    log.Printf("component %s at %s (%d actions)", c.Type, c.Prefix, len(c.Actions))
+//	}
+func (reg *Registry) Components() []ComponentDescription {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+
+	descs := make([]ComponentDescription, 0, len(reg.components))
+	for prefix, comp := range reg.components {
+		var desc ComponentDescription
+		if d, ok := comp.(describer); ok {
+			desc = d.describe()
+		} else {
+			desc = ComponentDescription{Prefix: prefix, Actions: []ActionDescription{}}
+		}
+		desc.Type = fmt.Sprintf("%T", comp)
+		descs = append(descs, desc)
+	}
+
+	sort.Slice(descs, func(i, j int) bool {
+		return descs[i].Prefix < descs[j].Prefix
+	})
+	return descs
+}
+
+// Routes returns every method and path served by the registry, sorted by
+// path and method. Each component contributes its GET render route followed
+// by one route per action.
+func (reg *Registry) Routes() []Route {
+	var routes []Route
+	for _, c := range reg.Components() {
+		routes = append(routes, Route{
+			Method:    http.MethodGet,
+			Path:      c.Prefix + "/",
+			Component: c.Type,
+		})
+		for _, a := range c.Actions {
+			routes = append(routes, Route{
+				Method:    a.Method,
+				Path:      a.Path,
+				Component: c.Type,
+				Action:    a.Name,
+			})
+		}
+	}
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	return routes
+}
+
+// DescribeHandler returns an HTTP handler that serves the registry's
+// component descriptions and routes as JSON.
+//
+// It is not mounted automatically. Mount it behind your own access control
+// if route information should not be public:
+//
+//	mux.Handle("/debug/components", adminOnly(reg.DescribeHandler()))
+func (reg *Registry) DescribeHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := struct {
+			Components []ComponentDescription `json:"components"`
+			Routes     []Route                `json:"routes"`
+		}{
+			Components: reg.Components(),
+			Routes:     reg.Routes(),
+		}
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(body); err != nil {
+			http.Error(w, "Internal error", http.StatusInternalServerError)
+		}
+	})
+}
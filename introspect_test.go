@@ -0,0 +1,97 @@
+package hxcmp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRegistryComponents(t *testing.T) {
+	reg := NewRegistry(make([]byte, 32))
+	plain := newWidget("plain")
+	secret := newWidget("secret")
+	secret.Sensitive()
+	reg.Add(plain, secret)
+
+	descs := reg.Components()
+	if len(descs) != 2 {
+		t.Fatalf("got %d components, want 2", len(descs))
+	}
+
+	byName := map[string]ComponentDescription{}
+	for _, d := range descs {
+		byName[d.Name] = d
+	}
+
+	d := byName["secret"]
+	if d.Prefix != secret.Prefix() {
+		t.Errorf("Prefix = %q, want %q", d.Prefix, secret.Prefix())
+	}
+	if d.Type != "*hxcmp.widget" {
+		t.Errorf("Type = %q, want %q", d.Type, "*hxcmp.widget")
+	}
+	if d.PropsType != "hxcmp.widgetProps" {
+		t.Errorf("PropsType = %q, want %q", d.PropsType, "hxcmp.widgetProps")
+	}
+	if !d.Sensitive || byName["plain"].Sensitive {
+		t.Error("sensitivity not reported correctly")
+	}
+
+	expected := []ActionDescription{
+		{Name: "remove", Method: http.MethodDelete, Path: secret.Prefix() + "/remove"},
+		{Name: "save", Method: http.MethodPost, Path: secret.Prefix() + "/save"},
+	}
+	if len(d.Actions) != len(expected) {
+		t.Fatalf("got %d actions, want %d", len(d.Actions), len(expected))
+	}
+	for i, a := range d.Actions {
+		if a != expected[i] {
+			t.Errorf("Actions[%d] = %+v, want %+v", i, a, expected[i])
+		}
+	}
+}
+
+func TestRegistryRoutes(t *testing.T) {
+	reg := NewRegistry(make([]byte, 32))
+	c := newWidget("widget")
+	reg.Add(c)
+
+	routes := reg.Routes()
+	expected := []Route{
+		{Method: http.MethodGet, Path: c.Prefix() + "/", Component: "*hxcmp.widget"},
+		{Method: http.MethodDelete, Path: c.Prefix() + "/remove", Component: "*hxcmp.widget", Action: "remove"},
+		{Method: http.MethodPost, Path: c.Prefix() + "/save", Component: "*hxcmp.widget", Action: "save"},
+	}
+	if len(routes) != len(expected) {
+		t.Fatalf("got %d routes, want %d: %+v", len(routes), len(expected), routes)
+	}
+	for i, r := range routes {
+		if r != expected[i] {
+			t.Errorf("routes[%d] = %+v, want %+v", i, r, expected[i])
+		}
+	}
+}
+
+func TestRegistryDescribeHandler(t *testing.T) {
+	reg := NewRegistry(make([]byte, 32))
+	reg.Add(newWidget("widget"))
+
+	rec := httptest.NewRecorder()
+	reg.DescribeHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+
+	var body struct {
+		Components []ComponentDescription `json:"components"`
+		Routes     []Route                `json:"routes"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(body.Components) != 1 || len(body.Routes) != 3 {
+		t.Errorf("unexpected body: %s", rec.Body.String())
+	}
+}
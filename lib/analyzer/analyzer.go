@@ -0,0 +1,384 @@
+// Package analyzer provides a go/analysis Analyzer that reports hxcmp
+// mistakes the compiler can't catch:
+//
+//   - Wire, URL and AbsoluteURL methods for an action the component doesn't
+//     register, which happens when generated code is stale
+//   - Action handlers that don't return hxcmp.Result[P] for the component's
+//     own props type P
+//   - Render methods that assign to the component's fields
+//   - hxcmp.New called inside a loop, where every iteration gets the same
+//     source-location prefix
+//
+// The analyzer runs as 'hxcmp vet' or under 'go vet -vettool=$(which hxcmp)',
+// and can be added to any multichecker.
+package analyzer
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// hxcmpPath is the import path of the hxcmp package.
+const hxcmpPath = "github.com/pthm/hxcmp"
+
+// Analyzer reports misuse of hxcmp components.
+var Analyzer = &analysis.Analyzer{
+	Name:      "hxcmp",
+	Doc:       "check hxcmp components for stale Wire calls, mistyped handlers, Render side effects and hxcmp.New in loops",
+	URL:       "https://github.com/pthm/hxcmp",
+	Requires:  []*analysis.Analyzer{inspect.Analyzer},
+	Run:       run,
+	FactTypes: []analysis.Fact{new(actionsFact)},
+}
+
+// actionsFact records the actions a component type registers, so Wire calls
+// can be checked from other packages.
+type actionsFact struct {
+	Names []string
+}
+
+func (*actionsFact) AFact() {}
+
+func (f *actionsFact) String() string {
+	return "actions(" + strings.Join(f.Names, ", ") + ")"
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	if !importsHxcmp(pass.Pkg) {
+		return nil, nil
+	}
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	exportActions(pass, insp)
+	checkWireCalls(pass, insp)
+	checkRender(pass, insp)
+	checkNewInLoop(pass, insp)
+
+	return nil, nil
+}
+
+// importsHxcmp reports whether pkg imports hxcmp directly. Packages that
+// don't can't declare or use components.
+func importsHxcmp(pkg *types.Package) bool {
+	for _, imp := range pkg.Imports() {
+		if imp.Path() == hxcmpPath {
+			return true
+		}
+	}
+	return false
+}
+
+// exportActions finds the c.Action(name, handler) calls in the package,
+// checks each handler's result type, and exports the action names of every
+// component type declared in the package.
+func exportActions(pass *analysis.Pass, insp *inspector.Inspector) {
+	actions := make(map[*types.TypeName][]string)
+	scope := pass.Pkg.Scope()
+	for _, name := range scope.Names() {
+		tn, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || tn.IsAlias() {
+			continue
+		}
+		if _, ok := componentProps(tn.Type()); ok {
+			actions[tn] = []string{}
+		}
+	}
+
+	insp.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+		if !ok || len(call.Args) != 2 {
+			return
+		}
+		fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+		if !ok || !isHxcmpMethod(fn, "Component", "Action") {
+			return
+		}
+		props, ok := hxcmpTypeArg(fn.Signature().Recv().Type(), "Component")
+		if !ok {
+			return
+		}
+
+		checkHandler(pass, call.Args[1], props)
+
+		tn := componentTypeName(pass, sel.X)
+		if tn == nil {
+			return
+		}
+		if _, ok := actions[tn]; !ok {
+			return
+		}
+		if tv := pass.TypesInfo.Types[call.Args[0]]; tv.Value != nil && tv.Value.Kind() == constant.String {
+			actions[tn] = append(actions[tn], constant.StringVal(tv.Value))
+		}
+	})
+
+	for tn, names := range actions {
+		sort.Strings(names)
+		pass.ExportObjectFact(tn, &actionsFact{Names: names})
+	}
+}
+
+// checkHandler reports an action handler that isn't a function returning
+// hxcmp.Result[P].
+func checkHandler(pass *analysis.Pass, handler ast.Expr, props types.Type) {
+	qual := func(p *types.Package) string {
+		if p == pass.Pkg {
+			return ""
+		}
+		return p.Name()
+	}
+	want := "hxcmp.Result[" + types.TypeString(props, qual) + "]"
+
+	sig, ok := pass.TypesInfo.TypeOf(handler).Underlying().(*types.Signature)
+	if !ok {
+		pass.Reportf(handler.Pos(), "action handler is not a function; want a func returning %s", want)
+		return
+	}
+	results := sig.Results()
+	if results.Len() == 1 {
+		result := results.At(0).Type()
+		if arg, ok := hxcmpTypeArg(result, "Result"); ok && types.Identical(arg, props) {
+			return
+		}
+		pass.Reportf(handler.Pos(), "action handler returns %s, want %s", types.TypeString(result, qual), want)
+		return
+	}
+	pass.Reportf(handler.Pos(), "action handler returns %s, want %s", types.TypeString(results, qual), want)
+}
+
+// checkWireCalls reports uses of generated Wire, URL and AbsoluteURL methods
+// for actions the component does not register.
+func checkWireCalls(pass *analysis.Pass, insp *inspector.Inspector) {
+	insp.Preorder([]ast.Node{(*ast.SelectorExpr)(nil)}, func(n ast.Node) {
+		sel := n.(*ast.SelectorExpr)
+		selection, ok := pass.TypesInfo.Selections[sel]
+		if !ok || selection.Kind() != types.MethodVal {
+			return
+		}
+		action, ok := wireAction(sel.Sel.Name)
+		if !ok || action == "Render" || !isGenerated(pass, selection.Obj()) {
+			return
+		}
+		tn := componentTypeName(pass, sel.X)
+		if tn == nil {
+			return
+		}
+		var fact actionsFact
+		if !pass.ImportObjectFact(tn, &fact) {
+			return
+		}
+		for _, name := range fact.Names {
+			if camelToTitle(name) == action || camelToTitle(name)+"For" == action {
+				return
+			}
+		}
+		pass.Reportf(sel.Sel.Pos(), "%s is generated for an action %s does not register; run 'hxcmp generate'",
+			sel.Sel.Name, tn.Name())
+	})
+}
+
+// wireAction returns the action part of a generated method name such as
+// WireEdit or AbsoluteURLEdit. For WireEditFor it is "EditFor"; callers
+// also accept the action with a "For" suffix.
+func wireAction(method string) (string, bool) {
+	// AbsoluteURL must be tried before URL
+	for _, prefix := range []string{"Wire", "AbsoluteURL", "URL"} {
+		if action, ok := strings.CutPrefix(method, prefix); ok && action != "" {
+			return action, true
+		}
+	}
+	return "", false
+}
+
+// isGenerated reports whether obj is declared in a *_hx.go file.
+func isGenerated(pass *analysis.Pass, obj types.Object) bool {
+	pos := pass.Fset.Position(obj.Pos())
+	return strings.HasSuffix(pos.Filename, "_hx.go")
+}
+
+// checkRender reports assignments to a component's fields inside its Render
+// method. A component is shared by every request, so Render must only read
+// from the receiver.
+func checkRender(pass *analysis.Pass, insp *inspector.Inspector) {
+	insp.Preorder([]ast.Node{(*ast.FuncDecl)(nil)}, func(n ast.Node) {
+		decl := n.(*ast.FuncDecl)
+		if decl.Name.Name != "Render" || decl.Recv == nil || decl.Body == nil {
+			return
+		}
+		recv := decl.Recv.List[0]
+		if len(recv.Names) == 0 {
+			return
+		}
+		obj := pass.TypesInfo.Defs[recv.Names[0]]
+		if obj == nil {
+			return
+		}
+		if _, ok := componentProps(obj.Type()); !ok {
+			return
+		}
+
+		report := func(lhs ast.Expr) {
+			if assignsThrough(pass, lhs, obj) {
+				pass.Reportf(lhs.Pos(), "Render assigns to %s; components are shared between requests, so Render must not modify the receiver",
+					types.ExprString(lhs))
+			}
+		}
+		ast.Inspect(decl.Body, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.AssignStmt:
+				if n.Tok != token.DEFINE {
+					for _, lhs := range n.Lhs {
+						report(lhs)
+					}
+				}
+			case *ast.IncDecStmt:
+				report(n.X)
+			}
+			return true
+		})
+	})
+}
+
+// assignsThrough reports whether assigning to lhs writes to memory reached
+// through recv, as in recv.field, recv.m[k] or *recv.p.
+func assignsThrough(pass *analysis.Pass, lhs ast.Expr, recv types.Object) bool {
+	depth := 0
+	for {
+		switch e := lhs.(type) {
+		case *ast.ParenExpr:
+			lhs = e.X
+		case *ast.SelectorExpr:
+			lhs, depth = e.X, depth+1
+		case *ast.IndexExpr:
+			lhs, depth = e.X, depth+1
+		case *ast.StarExpr:
+			lhs, depth = e.X, depth+1
+		case *ast.Ident:
+			// Reassigning the receiver variable itself is harmless
+			return depth > 0 && pass.TypesInfo.Uses[e] == recv
+		default:
+			return false
+		}
+	}
+}
+
+// checkNewInLoop reports hxcmp.New calls inside a for or range loop. The
+// component prefix is derived from the call site, so every iteration
+// creates a component with the same prefix.
+func checkNewInLoop(pass *analysis.Pass, insp *inspector.Inspector) {
+	insp.WithStack([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		call := n.(*ast.CallExpr)
+		fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+		if !ok || fn.Pkg() == nil || fn.Pkg().Path() != hxcmpPath || fn.Name() != "New" {
+			return true
+		}
+		// Walk outwards to the enclosing function
+		for i := len(stack) - 2; i >= 0; i-- {
+			switch stack[i].(type) {
+			case *ast.FuncLit, *ast.FuncDecl:
+				return true
+			case *ast.ForStmt, *ast.RangeStmt:
+				pass.Reportf(call.Pos(), "hxcmp.New called inside a loop; every iteration gets the same prefix because it is derived from the call site")
+				return true
+			}
+		}
+		return true
+	})
+}
+
+// componentProps returns the props type P of a type that embeds
+// *hxcmp.Component[P], or a pointer to one.
+func componentProps(t types.Type) (types.Type, bool) {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	st, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return nil, false
+	}
+	for i := 0; i < st.NumFields(); i++ {
+		if f := st.Field(i); f.Embedded() {
+			if props, ok := hxcmpTypeArg(f.Type(), "Component"); ok {
+				return props, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// componentTypeName returns the named component type of expr, which may be
+// the component itself or its embedded Component field (c.Component).
+func componentTypeName(pass *analysis.Pass, expr ast.Expr) *types.TypeName {
+	t := pass.TypesInfo.TypeOf(expr)
+	if _, ok := hxcmpTypeArg(t, "Component"); ok {
+		sel, ok := ast.Unparen(expr).(*ast.SelectorExpr)
+		if !ok {
+			return nil
+		}
+		t = pass.TypesInfo.TypeOf(sel.X)
+	}
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok {
+		return nil
+	}
+	if _, ok := componentProps(named); !ok {
+		return nil
+	}
+	return named.Origin().Obj()
+}
+
+// hxcmpTypeArg returns the type argument of t if it is hxcmp.<name>[T] or a
+// pointer to one.
+func hxcmpTypeArg(t types.Type, name string) (types.Type, bool) {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.TypeArgs().Len() != 1 {
+		return nil, false
+	}
+	obj := named.Obj()
+	if obj.Pkg() == nil || obj.Pkg().Path() != hxcmpPath || obj.Name() != name {
+		return nil, false
+	}
+	return named.TypeArgs().At(0), true
+}
+
+// isHxcmpMethod reports whether fn is the method hxcmp.<recv>.<name>.
+func isHxcmpMethod(fn *types.Func, recv, name string) bool {
+	if fn.Name() != name || fn.Pkg() == nil || fn.Pkg().Path() != hxcmpPath {
+		return false
+	}
+	r := fn.Signature().Recv()
+	if r == nil {
+		return false
+	}
+	_, ok := hxcmpTypeArg(r.Type(), recv)
+	return ok
+}
+
+// camelToTitle matches the generator's method naming: WireEdit for "edit".
+func camelToTitle(s string) string {
+	s = strings.TrimPrefix(s, "handle")
+	if len(s) == 0 {
+		return s
+	}
+	return string(unicode.ToUpper(rune(s[0]))) + s[1:]
+}
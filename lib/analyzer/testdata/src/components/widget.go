@@ -0,0 +1,81 @@
+package components
+
+import (
+	"context"
+
+	"github.com/pthm/hxcmp"
+)
+
+type WidgetProps struct {
+	ID int
+}
+
+type OtherProps struct{}
+
+type Widget struct { // want Widget:`actions\(delete, edit, other, value\)`
+	*hxcmp.Component[WidgetProps]
+	count int
+	cache map[int]string
+}
+
+func NewWidget() *Widget {
+	c := &Widget{Component: hxcmp.New[WidgetProps]("widget")}
+	c.Action("edit", c.handleEdit)
+	c.Action("delete", c.handleDelete).Method("DELETE")
+	c.Action("other", c.handleOther)          // want `action handler returns hxcmp.Result\[OtherProps\], want hxcmp.Result\[WidgetProps\]`
+	c.Component.Action("value", "not a func") // want `action handler is not a function; want a func returning hxcmp.Result\[WidgetProps\]`
+	return c
+}
+
+func (c *Widget) handleEdit(ctx context.Context, props WidgetProps) hxcmp.Result[WidgetProps] {
+	return hxcmp.OK(props)
+}
+
+func (c *Widget) handleDelete(ctx context.Context, props WidgetProps) hxcmp.Result[WidgetProps] {
+	c.count++ // Handlers may update state
+	return hxcmp.OK(props)
+}
+
+func (c *Widget) handleOther(ctx context.Context, props WidgetProps) hxcmp.Result[OtherProps] {
+	return hxcmp.OK(OtherProps{})
+}
+
+func (c *Widget) Render(ctx context.Context, props WidgetProps) string {
+	c.count++                  // want `Render assigns to c.count; components are shared between requests`
+	c.cache[props.ID] = "seen" // want `Render assigns to c.cache\[props.ID\]`
+	n := c.count
+	n++
+	render := func() {
+		c.count = n // want `Render assigns to c.count`
+	}
+	render()
+	c = nil
+	return ""
+}
+
+func (c *Widget) URLFor(path string) string {
+	return path
+}
+
+func NewWidgets(names []string) []*hxcmp.Component[WidgetProps] {
+	var out []*hxcmp.Component[WidgetProps]
+	for _, name := range names {
+		out = append(out, hxcmp.New[WidgetProps](name)) // want `hxcmp.New called inside a loop`
+	}
+	for i := 0; i < 2; i++ {
+		build := func() *hxcmp.Component[WidgetProps] {
+			return hxcmp.New[WidgetProps]("lazy")
+		}
+		out = append(out, build())
+	}
+	return append(out, hxcmp.New[WidgetProps]("single"))
+}
+
+func use(c *Widget) {
+	_ = c.WireEdit(WidgetProps{})
+	_ = c.WireRender(WidgetProps{})
+	_ = c.URLDelete(WidgetProps{})
+	_ = c.URLFor("/")
+	_ = c.WireArchive(WidgetProps{})            // want `WireArchive is generated for an action Widget does not register`
+	_ = c.AbsoluteURLArchive("", WidgetProps{}) // want `AbsoluteURLArchive is generated for an action Widget does not register`
+}
@@ -0,0 +1,19 @@
+// Package hxcmp is a minimal stand-in for the real package, declaring just
+// what the analyzer inspects.
+package hxcmp
+
+type Component[P any] struct{ name string }
+
+func New[P any](name string) *Component[P] { return &Component[P]{name: name} }
+
+type ActionBuilder struct{}
+
+func (ab *ActionBuilder) Method(m string) *ActionBuilder { return ab }
+
+func (c *Component[P]) Action(name string, handler any) *ActionBuilder { return &ActionBuilder{} }
+
+type Result[P any] struct{ props P }
+
+func OK[P any](props P) Result[P] { return Result[P]{props: props} }
+
+type Attributes map[string]any
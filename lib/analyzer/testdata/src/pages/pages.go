@@ -0,0 +1,17 @@
+package pages
+
+import (
+	"components"
+	"context"
+
+	"github.com/pthm/hxcmp"
+)
+
+var _ hxcmp.Attributes
+
+func page(w *components.Widget) {
+	_ = w.WireEdit(components.WidgetProps{})
+	_ = w.WireArchive(components.WidgetProps{}) // want `WireArchive is generated for an action Widget does not register`
+	_ = w.WireEditFor(context.Background(), components.WidgetProps{})
+	_ = w.WireArchiveFor(context.Background(), components.WidgetProps{}) // want `WireArchiveFor is generated for an action Widget does not register`
+}
@@ -0,0 +1,247 @@
+package generator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PropConstraints are the validation rules declared in a props field's hx
+// tag. The generated HXDecode applies the default and returns a
+// *hxcmp.PropsError for violations, so bad props never reach Hydrate:
+//
+//	ID     string `hx:"id,required"`
+//	Page   int    `hx:"page,default=1,min=1,max=100"`
+//	Status string `hx:"status,oneof=open|closed"`
+//
+// Required means a string or time.Time field must be non-zero, and a numeric
+// or bool field must be present in the encoded props: 0 and false are valid
+// values. Min and Max bound the value of numeric fields and the length of
+// strings.
+type PropConstraints struct {
+	Required bool
+	Default  string   // Replaces a zero value; "" for none
+	Min      string   // "" for none
+	Max      string   // "" for none
+	OneOf    []string // Allowed values
+}
+
+// parse records a single tag option. Unknown options and options missing
+// their value are errors, so a misspelled constraint isn't silently
+// unenforced.
+func (c *PropConstraints) parse(opt string) error {
+	name, value, hasValue := strings.Cut(opt, "=")
+	switch name {
+	case "required":
+		if hasValue {
+			return fmt.Errorf("option %q takes no value", name)
+		}
+		c.Required = true
+		return nil
+	case "default", "min", "max", "oneof":
+		if value == "" {
+			return fmt.Errorf("option %q needs a value, as in %s=...", name, name)
+		}
+	default:
+		return fmt.Errorf("unknown hx tag option %q", opt)
+	}
+
+	switch name {
+	case "default":
+		c.Default = value
+	case "min":
+		c.Min = value
+	case "max":
+		c.Max = value
+	case "oneof":
+		c.OneOf = strings.Split(value, "|")
+	}
+	return nil
+}
+
+// IsZero reports whether no constraints are declared.
+func (c PropConstraints) IsZero() bool {
+	return !c.Required && c.Default == "" && c.Min == "" && c.Max == "" && len(c.OneOf) == 0
+}
+
+// String formats the constraints as they appear in the hx tag.
+func (c PropConstraints) String() string {
+	var opts []string
+	if c.Required {
+		opts = append(opts, "required")
+	}
+	if c.Default != "" {
+		opts = append(opts, "default="+c.Default)
+	}
+	if c.Min != "" {
+		opts = append(opts, "min="+c.Min)
+	}
+	if c.Max != "" {
+		opts = append(opts, "max="+c.Max)
+	}
+	if len(c.OneOf) > 0 {
+		opts = append(opts, "oneof="+strings.Join(c.OneOf, "|"))
+	}
+	return strings.Join(opts, ",")
+}
+
+// check reports constraints that don't apply to the field's type or whose
+// values don't parse as it, which would otherwise generate code that
+// doesn't compile.
+func (c PropConstraints) check(f PropField) error {
+	if c.IsZero() {
+		return nil
+	}
+	if f.Exclude {
+		return fmt.Errorf("constraints %q on a field excluded from props", c)
+	}
+
+	switch {
+	case f.Type == "string":
+		for _, n := range []string{c.Min, c.Max} {
+			if n == "" {
+				continue
+			}
+			if _, err := strconv.Atoi(n); err != nil {
+				return fmt.Errorf("min and max of a string are lengths, got %q", n)
+			}
+		}
+		return nil
+	case isNumericType(f.Type):
+		values := append([]string{c.Default, c.Min, c.Max}, c.OneOf...)
+		for _, v := range values {
+			if v == "" {
+				continue
+			}
+			if err := parseNumber(f.Type, v); err != nil {
+				return fmt.Errorf("%q is not a valid %s", v, f.Type)
+			}
+		}
+		return nil
+	case f.Type == "bool" || f.Type == "time.Time":
+		// A default would replace every false or zero time
+		if c.Default != "" || c.Min != "" || c.Max != "" || len(c.OneOf) > 0 {
+			return fmt.Errorf("%s fields support only required", f.Type)
+		}
+		return nil
+	default:
+		return fmt.Errorf("constraints are not supported for type %s", f.Type)
+	}
+}
+
+// isNumericType reports whether typeName is a Go integer or float type.
+func isNumericType(typeName string) bool {
+	switch typeName {
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64",
+		"float32", "float64":
+		return true
+	}
+	return false
+}
+
+// parseNumber checks that v is a valid literal of the numeric type.
+func parseNumber(typeName, v string) error {
+	var err error
+	switch {
+	case strings.HasPrefix(typeName, "float"):
+		_, err = strconv.ParseFloat(v, 64)
+	case strings.HasPrefix(typeName, "uint"):
+		_, err = strconv.ParseUint(v, 10, intBits(typeName))
+	default:
+		_, err = strconv.ParseInt(v, 10, intBits(typeName))
+	}
+	return err
+}
+
+// checkFieldCode generates the code that applies a field's default and
+// checks its constraints after decoding.
+func checkFieldCode(f PropField) string {
+	c := f.Constraints
+	if f.Exclude || c.IsZero() {
+		return ""
+	}
+
+	key := fieldKey(f)
+	field := "p." + f.Name
+	var b strings.Builder
+
+	if c.Default != "" {
+		fmt.Fprintf(&b, "if %s {\n\t%s = %s\n}\n", zeroExpr(f.Type, field), field, constraintLiteral(f.Type, c.Default))
+	}
+	if c.Required {
+		fmt.Fprintf(&b, "check.Required(%q, m[%q], %s)\n", key, key, requiredExpr(f.Type, key, field))
+	}
+	if f.Type == "string" {
+		if c.Min != "" {
+			fmt.Fprintf(&b, "check.MinLen(%q, %s, %s)\n", key, field, c.Min)
+		}
+		if c.Max != "" {
+			fmt.Fprintf(&b, "check.MaxLen(%q, %s, %s)\n", key, field, c.Max)
+		}
+	} else {
+		if c.Min != "" {
+			fmt.Fprintf(&b, "hxcmp.CheckMin(&check, %q, %s, %s)\n", key, field, c.Min)
+		}
+		if c.Max != "" {
+			fmt.Fprintf(&b, "hxcmp.CheckMax(&check, %q, %s, %s)\n", key, field, c.Max)
+		}
+	}
+	if len(c.OneOf) > 0 {
+		allowed := make([]string, len(c.OneOf))
+		for i, v := range c.OneOf {
+			allowed[i] = constraintLiteral(f.Type, v)
+		}
+		fmt.Fprintf(&b, "hxcmp.CheckOneOf(&check, %q, %s, %s)\n", key, field, strings.Join(allowed, ", "))
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// constraintLiteral returns a tag value as a Go literal of the field type.
+// Values have been validated by PropConstraints.check.
+func constraintLiteral(typeName, v string) string {
+	if typeName == "string" {
+		return strconv.Quote(v)
+	}
+	return v
+}
+
+// zeroExpr returns an expression that is true when field is the zero value
+// of its type.
+func zeroExpr(typeName, field string) string {
+	switch {
+	case typeName == "string":
+		return field + ` == ""`
+	case typeName == "bool":
+		return "!" + field
+	case typeName == "time.Time":
+		return field + ".IsZero()"
+	default:
+		return field + " == 0"
+	}
+}
+
+// requiredExpr returns an expression that is true when a required field
+// has a value. Strings and times must be non-zero; numbers and bools only
+// need their key in the decoded map m, so 0 and false satisfy required.
+func requiredExpr(typeName, key, field string) string {
+	switch typeName {
+	case "string":
+		return field + ` != ""`
+	case "time.Time":
+		return "!" + field + ".IsZero()"
+	default:
+		return fmt.Sprintf("m[%q] != nil", key)
+	}
+}
+
+// hasConstraints reports whether any props field declares constraints.
+func hasConstraints(props []PropField) bool {
+	for _, f := range props {
+		if !f.Exclude && !f.Constraints.IsZero() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseHXTagConstraints(t *testing.T) {
+	key, omitEmpty, exclude, c, err := parseHXTag(`json:"x" hx:"status,omitempty,required,default=open,oneof=open|closed"`)
+	if err != nil {
+		t.Fatalf("parseHXTag() error = %v", err)
+	}
+	if key != "status" || !omitEmpty || exclude {
+		t.Errorf("key = %q, omitempty = %v, exclude = %v", key, omitEmpty, exclude)
+	}
+	if !c.Required || c.Default != "open" || strings.Join(c.OneOf, ",") != "open,closed" {
+		t.Errorf("constraints = %+v", c)
+	}
+	if got := c.String(); got != "required,default=open,oneof=open|closed" {
+		t.Errorf("String() = %q", got)
+	}
+
+	_, _, _, c, _ = parseHXTag(`hx:"page,min=1,max=100"`)
+	if c.Min != "1" || c.Max != "100" || c.Required {
+		t.Errorf("constraints = %+v", c)
+	}
+}
+
+func TestParseHXTagInvalidOptions(t *testing.T) {
+	tests := []struct {
+		tag     string
+		wantErr string
+	}{
+		{"page,minimum=1", `unknown hx tag option "minimum=1"`},
+		{"page,requried", `unknown hx tag option "requried"`},
+		{"page,min", `option "min" needs a value`},
+		{"page,max=", `option "max" needs a value`},
+		{"status,oneof", `option "oneof" needs a value`},
+		{"page,default", `option "default" needs a value`},
+		{"id,required=true", `option "required" takes no value`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			_, _, _, _, err := parseHXTag(`hx:"` + tt.tag + `"`)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseHXTag() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGenerateInvalidConstraint(t *testing.T) {
+	dir := writeManifestPackage(t)
+	src := strings.Replace(manifestSource, "`hx:\"page,omitempty\"`", "`hx:\"page,omitempty,mni=1\"`", 1)
+	if err := os.WriteFile(filepath.Join(dir, "item.go"), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := New(Options{}).Generate(dir)
+	if err == nil {
+		t.Fatal("Generate() error = nil, want invalid tag option")
+	}
+	want := filepath.Join(dir, "item.go") + `:13:2: ItemProps.Page: unknown hx tag option "mni=1"`
+	if !strings.Contains(err.Error(), want) {
+		t.Errorf("Generate() error = %v, want %q", err, want)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "item_hx.go")); !os.IsNotExist(err) {
+		t.Errorf("item_hx.go written despite invalid tag: %v", err)
+	}
+}
+
+func TestPropConstraintsCheck(t *testing.T) {
+	tests := []struct {
+		typ     string
+		tag     string
+		wantErr string
+	}{
+		{"int", "n,min=1,max=10,default=5", ""},
+		{"uint8", "n,max=255,oneof=1|2", ""},
+		{"float64", "n,min=0.5", ""},
+		{"string", "s,required,min=2,max=5,oneof=ab|cd", ""},
+		{"bool", "b,required", ""},
+		{"time.Time", "t,required", ""},
+		{"int", "n,min=low", `"low" is not a valid int`},
+		{"uint8", "n,max=256", `"256" is not a valid uint8`},
+		{"string", "s,min=two", "lengths"},
+		{"bool", "b,default=true", "support only required"},
+		{"time.Time", "t,min=1", "support only required"},
+		{"[]string", "s,required", "not supported for type []string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.typ+" "+tt.tag, func(t *testing.T) {
+			f := PropField{Name: "F", Type: tt.typ}
+			var err error
+			f.Tag, f.OmitEmpty, f.Exclude, f.Constraints, err = parseHXTag(`hx:"` + tt.tag + `"`)
+			if err != nil {
+				t.Fatalf("parseHXTag() error = %v", err)
+			}
+			err = f.Constraints.check(f)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("check() error = %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("check() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckFieldCode(t *testing.T) {
+	f := PropField{Name: "Page", Type: "int", Tag: "page"}
+	if got := checkFieldCode(f); got != "" {
+		t.Errorf("unconstrained field generated %q", got)
+	}
+
+	f.Constraints = PropConstraints{Required: true, Default: "1", Min: "1", Max: "9"}
+	want := `if p.Page == 0 {
+	p.Page = 1
+}
+check.Required("page", m["page"], m["page"] != nil)
+hxcmp.CheckMin(&check, "page", p.Page, 1)
+hxcmp.CheckMax(&check, "page", p.Page, 9)`
+	if got := checkFieldCode(f); got != want {
+		t.Errorf("checkFieldCode() =\n%s\nwant\n%s", got, want)
+	}
+
+	s := PropField{Name: "Mode", Type: "string", Tag: "mode",
+		Constraints: PropConstraints{Min: "2", OneOf: []string{"a\"b", "cd"}}}
+	want = `check.MinLen("mode", p.Mode, 2)
+hxcmp.CheckOneOf(&check, "mode", p.Mode, "a\"b", "cd")`
+	if got := checkFieldCode(s); got != want {
+		t.Errorf("checkFieldCode() =\n%s\nwant\n%s", got, want)
+	}
+}
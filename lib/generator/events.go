@@ -0,0 +1,454 @@
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// emitsPrefix and listensPrefix start the header lines of hx_helpers.go
+// recording the package's events, so packages skipped as unchanged still
+// take part in the listener check.
+const (
+	emitsPrefix   = "// Emits: "
+	listensPrefix = "// Listens: "
+)
+
+// EventInfo describes an event declared with Emits or Listens.
+type EventInfo struct {
+	Name    string // Event name (e.g., "todo:changed")
+	Ident   string // Go identifier suffix (e.g., "TodoChanged")
+	Payload string // Payload struct type for Emits, if any
+}
+
+// eventUse is a listened event, for the check that every listened event is
+// emitted somewhere.
+type eventUse struct {
+	event string
+	file  string // Source file of the listener; empty if recorded by a previous run
+}
+
+// findEvents finds the events declared with Emits and Listens calls in a
+// component's file.
+func (g *Generator) findEvents(file *ast.File) (emits []EventInfo, listens []EventInfo, err error) {
+	emitted := make(map[string]EventInfo)
+	listened := make(map[string]EventInfo)
+
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Body == nil {
+			continue
+		}
+
+		ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+			callExpr, ok := n.(*ast.CallExpr)
+			if !ok || err != nil {
+				return err == nil
+			}
+			selExpr, ok := callExpr.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+
+			switch selExpr.Sel.Name {
+			case "Emits":
+				var ev EventInfo
+				if ev, err = g.extractEmits(callExpr); err != nil {
+					return false
+				}
+				if prev, ok := emitted[ev.Name]; ok && prev.Payload != ev.Payload {
+					err = fmt.Errorf("event %q is declared with payloads %s and %s", ev.Name, payloadName(prev), payloadName(ev))
+					return false
+				}
+				emitted[ev.Name] = ev
+			case "Listens":
+				for _, arg := range callExpr.Args {
+					name, ok := stringLiteral(arg)
+					if !ok {
+						err = fmt.Errorf("Listens: event names must be string literals")
+						return false
+					}
+					var ev EventInfo
+					if ev, err = newEventInfo(name); err != nil {
+						return false
+					}
+					listened[name] = ev
+				}
+			}
+			return true
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return sortedEvents(emitted), sortedEvents(listened), nil
+}
+
+// extractEmits extracts the event from a c.Emits("name", Payload{}) call.
+func (g *Generator) extractEmits(callExpr *ast.CallExpr) (EventInfo, error) {
+	if len(callExpr.Args) == 0 || len(callExpr.Args) > 2 {
+		return EventInfo{}, fmt.Errorf("Emits: want an event name and an optional payload")
+	}
+	name, ok := stringLiteral(callExpr.Args[0])
+	if !ok {
+		return EventInfo{}, fmt.Errorf("Emits: event name must be a string literal")
+	}
+	ev, err := newEventInfo(name)
+	if err != nil {
+		return EventInfo{}, err
+	}
+	if len(callExpr.Args) == 1 {
+		return ev, nil
+	}
+
+	payload := callExpr.Args[1]
+	if u, ok := payload.(*ast.UnaryExpr); ok && u.Op == token.AND {
+		payload = u.X
+	}
+	if lit, ok := payload.(*ast.CompositeLit); ok {
+		if ident, ok := lit.Type.(*ast.Ident); ok {
+			ev.Payload = ident.Name
+			return ev, nil
+		}
+	}
+	return EventInfo{}, fmt.Errorf("event %q: payload must be a struct literal of a type declared in this package, e.g. %sPayload{}", name, ev.Ident)
+}
+
+// newEventInfo validates an event name and derives its identifier.
+func newEventInfo(name string) (EventInfo, error) {
+	ident := eventIdent(name)
+	if ident == "" || strings.ContainsAny(name, " \t\n,") {
+		return EventInfo{}, fmt.Errorf("invalid event name %q", name)
+	}
+	return EventInfo{Name: name, Ident: ident}, nil
+}
+
+// eventIdent converts an event name to a Go identifier suffix by title-casing
+// the runs of letters and digits: "todo:changed" becomes "TodoChanged" and
+// "item-saved.v2" becomes "ItemSavedV2". Returns "" if the result would not
+// start with a letter.
+func eventIdent(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	ident := b.String()
+	if ident == "" || !unicode.IsLetter([]rune(ident)[0]) {
+		return ""
+	}
+	return ident
+}
+
+// stringLiteral returns the value of a string literal expression.
+func stringLiteral(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	s, err := strconv.Unquote(lit.Value)
+	return s, err == nil
+}
+
+// payloadName describes an event payload for error messages.
+func payloadName(ev EventInfo) string {
+	if ev.Payload == "" {
+		return "none"
+	}
+	return ev.Payload
+}
+
+// sortedEvents returns the events in a map sorted by name.
+func sortedEvents(m map[string]EventInfo) []EventInfo {
+	events := make([]EventInfo, 0, len(m))
+	for _, ev := range m {
+		events = append(events, ev)
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Name < events[j].Name
+	})
+	return events
+}
+
+// packageEvents holds the events declared by the components of a package,
+// from which hx_helpers.go declares constants and helpers.
+type packageEvents struct {
+	Emits   []EventInfo
+	Listens []EventInfo
+	All     []EventInfo // Emitted and listened events, once each
+}
+
+// collectEvents merges the events declared by a package's components. An
+// event emitted by several components must have the same payload, and
+// distinct events must not map to the same identifier.
+func collectEvents(components []*ComponentInfo) (*packageEvents, error) {
+	emitted := make(map[string]EventInfo)
+	emitter := make(map[string]string)
+	listened := make(map[string]EventInfo)
+	all := make(map[string]EventInfo)
+
+	for _, comp := range components {
+		for _, ev := range comp.Emits {
+			if prev, ok := emitted[ev.Name]; ok && prev.Payload != ev.Payload {
+				return nil, fmt.Errorf("event %q is emitted with payload %s by %s and %s by %s",
+					ev.Name, payloadName(prev), emitter[ev.Name], payloadName(ev), comp.TypeName)
+			}
+			emitted[ev.Name] = ev
+			emitter[ev.Name] = comp.TypeName
+			all[ev.Name] = ev
+		}
+		for _, ev := range comp.Listens {
+			listened[ev.Name] = ev
+			if _, ok := all[ev.Name]; !ok {
+				all[ev.Name] = ev
+			}
+		}
+	}
+
+	events := &packageEvents{
+		Emits:   sortedEvents(emitted),
+		Listens: sortedEvents(listened),
+		All:     sortedEvents(all),
+	}
+	idents := make(map[string]string)
+	for _, ev := range events.All {
+		if other, ok := idents[ev.Ident]; ok {
+			return nil, fmt.Errorf("events %q and %q both generate Event%s", other, ev.Name, ev.Ident)
+		}
+		idents[ev.Ident] = ev.Name
+	}
+	return events, nil
+}
+
+// header returns the hx_helpers.go header lines recording the events.
+func (e *packageEvents) header() string {
+	var b strings.Builder
+	if len(e.Emits) > 0 {
+		b.WriteString("\n" + emitsPrefix + joinEventNames(e.Emits))
+	}
+	if len(e.Listens) > 0 {
+		b.WriteString("\n" + listensPrefix + joinEventNames(e.Listens))
+	}
+	return b.String()
+}
+
+// joinEventNames joins event names with spaces, which event names can't
+// contain.
+func joinEventNames(events []EventInfo) string {
+	names := make([]string, len(events))
+	for i, ev := range events {
+		names[i] = ev.Name
+	}
+	return strings.Join(names, " ")
+}
+
+// render returns the declarations hx_helpers.go holds for the events.
+func (e *packageEvents) render() (string, error) {
+	if len(e.All) == 0 {
+		return "", nil
+	}
+	var buf bytes.Buffer
+	if err := eventsTmpl.Execute(&buf, e); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+var eventsTmpl = template.Must(template.New("events").Parse(eventsTemplate))
+
+const eventsTemplate = `
+import (
+{{- if .Listens}}
+	"github.com/a-h/templ"
+{{- end}}
+{{- if or .Emits .Listens}}
+	"github.com/pthm/hxcmp"
+{{- end}}
+)
+
+// Events declared by components in this package.
+const (
+{{- range .All}}
+	Event{{.Ident}} = {{printf "%q" .Name}}
+{{- end}}
+)
+{{range .Emits}}
+// Emit{{.Ident}} triggers the {{printf "%q" .Name}} event on result.
+{{- if .Payload}}
+// The payload is sent as the event detail.{{end}}
+func Emit{{.Ident}}[P any](result hxcmp.Result[P]{{if .Payload}}, payload {{.Payload}}{{end}}) hxcmp.Result[P] {
+	return result.Trigger(Event{{.Ident}}{{if .Payload}}, hxcmp.EventDetail(payload){{end}})
+}
+{{end}}
+{{- range .Listens}}
+// Listen{{.Ident}} returns the hx-trigger attribute that fires when the
+// {{printf "%q" .Name}} event is triggered.
+func Listen{{.Ident}}() templ.Attributes {
+	return hxcmp.Listen(Event{{.Ident}})
+}
+{{end}}`
+
+// recordedEvents returns the events recorded in a package's hx_helpers.go by
+// the last run.
+func recordedEvents(pkgPath string) (emits []string, listens []eventUse) {
+	emits = strings.Fields(recordedHeader(pkgPath, emitsPrefix))
+	for _, name := range strings.Fields(recordedHeader(pkgPath, listensPrefix)) {
+		listens = append(listens, eventUse{event: name})
+	}
+	return emits, listens
+}
+
+// checkListeners reports every listened event that no component emits. Events
+// are looked up in the packages of the run and in those recorded by earlier
+// runs for the rest of the module. An event that is still unresolved is an
+// error when every component package of the module has been generated, and
+// otherwise a warning, since an emitter may be in a package that never was.
+func (g *Generator) checkListeners(runs []*pkgRun) {
+	emitted := make(map[string]bool)
+	for _, r := range runs {
+		for _, name := range r.emits {
+			emitted[name] = true
+		}
+	}
+	complete := true
+	for _, dir := range otherModulePackages(runs) {
+		if !hasRecordedState(dir) {
+			if g.hasComponents(dir) {
+				complete = false
+			}
+			continue
+		}
+		emits, _ := recordedEvents(dir)
+		for _, name := range emits {
+			emitted[name] = true
+		}
+	}
+
+	for _, r := range runs {
+		for _, use := range r.listens {
+			if emitted[use.event] {
+				continue
+			}
+			err := fmt.Errorf("event %q is listened to but no component emits it", use.event)
+			if !complete {
+				r.diagnose(SeverityWarning, use.file, err.Error()+" (some component packages of the module were not generated)")
+				continue
+			}
+			r.diagnose(SeverityError, use.file, err.Error())
+			r.err = errors.Join(r.err, err)
+		}
+	}
+}
+
+// otherModulePackages returns the package directories of the modules holding
+// the run's packages that the run doesn't include. Packages outside a module
+// have no others.
+func otherModulePackages(runs []*pkgRun) []string {
+	inRun := make(map[string]bool)
+	roots := make(map[string]bool)
+	for _, r := range runs {
+		dir, err := filepath.Abs(r.path)
+		if err != nil {
+			continue
+		}
+		inRun[dir] = true
+		if root := moduleRoot(dir); root != "" {
+			roots[root] = true
+		}
+	}
+
+	var others []string
+	for root := range roots {
+		filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || !d.IsDir() {
+				return nil
+			}
+			base := d.Name()
+			if path != root && (strings.HasPrefix(base, ".") || base == "vendor" || base == "testdata" ||
+				fileExists(filepath.Join(path, "go.mod"))) {
+				return filepath.SkipDir
+			}
+			if !inRun[path] && hasGoFiles(path) {
+				others = append(others, path)
+			}
+			return nil
+		})
+	}
+	sort.Strings(others)
+	return others
+}
+
+// moduleRoot returns the directory of the go.mod enclosing dir, or "" if
+// there is none.
+func moduleRoot(dir string) string {
+	for {
+		if fileExists(filepath.Join(dir, "go.mod")) {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// hasGoFiles reports whether dir holds non-test Go files.
+func hasGoFiles(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".go") && !strings.HasSuffix(entry.Name(), "_test.go") {
+			return true
+		}
+	}
+	return false
+}
+
+// hasComponents reports whether the package in dir declares components. A
+// package that can't be parsed is assumed to, so its events aren't ignored.
+func (g *Generator) hasComponents(dir string) bool {
+	pkgs, err := g.parsePackage(dir)
+	if err != nil {
+		return true
+	}
+	for _, pkg := range pkgs {
+		components, err := g.findComponents(pkg)
+		if err != nil || len(components) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// hasRecordedState reports whether an earlier run recorded the events of the
+// package in dir.
+func hasRecordedState(dir string) bool {
+	return fileExists(filepath.Join(dir, helpersFile))
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,220 @@
+package generator
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"log"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEventIdent(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"todo:changed", "TodoChanged"},
+		{"item-saved.v2", "ItemSavedV2"},
+		{"refresh", "Refresh"},
+		{"HX:Reload", "HXReload"},
+		{"2fa:done", ""},
+		{"::", ""},
+	}
+	for _, tt := range tests {
+		if got := eventIdent(tt.name); got != tt.want {
+			t.Errorf("eventIdent(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFindEvents(t *testing.T) {
+	src := `package components
+
+func NewItemView() *ItemView {
+	c := &ItemView{}
+	c.Emits("item:saved", ItemSaved{}).Emits("item:deleted")
+	c.Emits("item:saved", &ItemSaved{})
+	c.Listens("filter:changed", "item:deleted")
+	return c
+}
+`
+	file, err := parser.ParseFile(token.NewFileSet(), "item.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	emits, listens, err := New(Options{}).findEvents(file)
+	if err != nil {
+		t.Fatalf("findEvents() error = %v", err)
+	}
+	want := []EventInfo{
+		{Name: "item:deleted", Ident: "ItemDeleted"},
+		{Name: "item:saved", Ident: "ItemSaved", Payload: "ItemSaved"},
+	}
+	if len(emits) != len(want) || emits[0] != want[0] || emits[1] != want[1] {
+		t.Errorf("emits = %+v, want %+v", emits, want)
+	}
+	if len(listens) != 2 || listens[0].Name != "filter:changed" || listens[1].Ident != "ItemDeleted" {
+		t.Errorf("listens = %+v", listens)
+	}
+}
+
+func TestFindEventsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		call string
+		want string
+	}{
+		{"name not literal", `c.Emits(name)`, "must be a string literal"},
+		{"listen not literal", `c.Listens("a", name)`, "must be string literals"},
+		{"invalid name", `c.Emits("9lives")`, "invalid event name"},
+		{"payload from other package", `c.Emits("a", models.Item{})`, "payload must be a struct literal"},
+		{"payload not literal", `c.Emits("a", item)`, "payload must be a struct literal"},
+		{"conflicting payloads", `c.Emits("a", A{}); c.Emits("a")`, "declared with payloads A and none"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := "package components\n\nfunc New() { " + tt.call + " }\n"
+			file, err := parser.ParseFile(token.NewFileSet(), "item.go", src, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, _, err = New(Options{}).findEvents(file)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("findEvents() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollectEventsConflicts(t *testing.T) {
+	a := &ComponentInfo{TypeName: "A", Emits: []EventInfo{{Name: "x", Ident: "X", Payload: "P"}}}
+	b := &ComponentInfo{TypeName: "B", Emits: []EventInfo{{Name: "x", Ident: "X"}}}
+	if _, err := collectEvents([]*ComponentInfo{a, b}); err == nil || !strings.Contains(err.Error(), "payload P by A and none by B") {
+		t.Errorf("payload conflict: error = %v", err)
+	}
+
+	c := &ComponentInfo{TypeName: "C", Listens: []EventInfo{{Name: "x-y", Ident: "XY"}, {Name: "x:y", Ident: "XY"}}}
+	if _, err := collectEvents([]*ComponentInfo{c}); err == nil || !strings.Contains(err.Error(), "both generate EventXY") {
+		t.Errorf("ident conflict: error = %v", err)
+	}
+}
+
+func TestGenerateListenerWithoutEmitter(t *testing.T) {
+	root := t.TempDir()
+	emitter := filepath.Join(root, "emitter")
+	writeFiles(t, emitter, map[string]string{
+		"item.go": strings.Replace(manifestSource, `c.Action("save", c.handleSave)`,
+			`c.Action("save", c.handleSave)
+	c.Emits("item:saved", ItemProps{})`, 1),
+	})
+	listener := filepath.Join(root, "listener")
+	writeFiles(t, listener, map[string]string{
+		"item.go": strings.Replace(manifestSource, `c.Action("save", c.handleSave)`,
+			`c.Action("save", c.handleSave)
+	c.Listens("item:saved", "item:svaed")`, 1),
+	})
+
+	// Skipped packages are checked from the events recorded by the first run
+	for _, run := range []string{"first", "unchanged"} {
+		g := New(Options{Logger: log.New(&bytes.Buffer{}, "", 0)})
+		report, err := g.GenerateReport(emitter, listener)
+		if err == nil || !strings.Contains(err.Error(), `"item:svaed"`) || strings.Contains(err.Error(), `"item:saved"`) {
+			t.Fatalf("%s run: error = %v", run, err)
+		}
+		if run == "unchanged" && !report.Packages[1].Skipped {
+			t.Fatalf("%s run: listener package not skipped", run)
+		}
+		if len(report.Diagnostics) != 1 || report.Diagnostics[0].Package != listener {
+			t.Errorf("%s run: diagnostics = %+v", run, report.Diagnostics)
+		}
+	}
+
+	helpers := readFile(t, filepath.Join(emitter, helpersFile))
+	for _, want := range []string{
+		"// Emits: item:saved\n",
+		`EventItemSaved = "item:saved"`,
+		"func EmitItemSaved[P any](result hxcmp.Result[P], payload ItemProps) hxcmp.Result[P]",
+	} {
+		if !strings.Contains(helpers, want) {
+			t.Errorf("emitter hx_helpers.go missing %q:\n%s", want, helpers)
+		}
+	}
+	if helpers := readFile(t, filepath.Join(listener, helpersFile)); !strings.Contains(helpers, "func ListenItemSvaed() templ.Attributes") {
+		t.Errorf("listener hx_helpers.go:\n%s", helpers)
+	}
+
+	// Listening only to emitted events succeeds
+	writeFiles(t, listener, map[string]string{
+		"item.go": strings.Replace(manifestSource, `c.Action("save", c.handleSave)`,
+			`c.Action("save", c.handleSave)
+	c.Listens("item:saved")`, 1),
+	})
+	if err := New(Options{Logger: log.New(&bytes.Buffer{}, "", 0)}).Generate(emitter, listener); err != nil {
+		t.Errorf("Generate() error = %v", err)
+	}
+}
+
+func TestGeneratePartialRunListeners(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{"go.mod": "module example.com/app\n"})
+	emitter := filepath.Join(root, "emitter")
+	writeFiles(t, emitter, map[string]string{
+		"item.go": strings.Replace(manifestSource, `c.Action("save", c.handleSave)`,
+			`c.Action("save", c.handleSave)
+	c.Emits("item:saved", ItemProps{})`, 1),
+	})
+	listener := filepath.Join(root, "listener")
+	listen := func(events string) {
+		writeFiles(t, listener, map[string]string{
+			"item.go": strings.Replace(manifestSource, `c.Action("save", c.handleSave)`,
+				`c.Action("save", c.handleSave)
+	c.Listens(`+events+`)`, 1),
+		})
+	}
+	pending := filepath.Join(root, "pending")
+	writeFiles(t, pending, map[string]string{"item.go": manifestSource})
+	// Packages without components never have recorded state
+	store := filepath.Join(root, "store")
+	writeFiles(t, store, map[string]string{"store.go": "package store\n"})
+	generate := func(patterns ...string) (*Report, error) {
+		return New(Options{Logger: log.New(&bytes.Buffer{}, "", 0)}).GenerateReport(patterns...)
+	}
+
+	// The emitter is known from the events recorded by its last run
+	listen(`"item:saved"`)
+	if _, err := generate(emitter); err != nil {
+		t.Fatalf("emitter: error = %v", err)
+	}
+	report, err := generate(listener)
+	if err != nil || len(report.Diagnostics) != 0 {
+		t.Fatalf("listener only: error = %v, diagnostics = %+v", err, report.Diagnostics)
+	}
+
+	// Unresolved events are warnings while some component packages weren't
+	// generated
+	listen(`"item:saved", "item:svaed"`)
+	report, err = generate(listener)
+	if err != nil {
+		t.Fatalf("listener only: error = %v", err)
+	}
+	if len(report.Diagnostics) != 1 || report.Diagnostics[0].Severity != SeverityWarning ||
+		!strings.Contains(report.Diagnostics[0].Message, `"item:svaed"`) {
+		t.Errorf("listener only: diagnostics = %+v", report.Diagnostics)
+	}
+
+	// Once every component package was generated they are errors, even
+	// though the component-free store package never is
+	if _, err := generate(pending); err != nil {
+		t.Fatalf("pending: error = %v", err)
+	}
+	if _, err := generate(listener); err == nil || !strings.Contains(err.Error(), `"item:svaed"`) {
+		t.Errorf("listener only, all generated: error = %v", err)
+	}
+
+	// and when the run covers the module
+	if _, err := generate(root + "/..."); err == nil || !strings.Contains(err.Error(), `"item:svaed"`) {
+		t.Errorf("whole module: error = %v", err)
+	}
+}
@@ -0,0 +1,185 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// Hook generates project-specific code for a component. Hooks are registered
+// in Options by a custom main that drives the generator:
+//
+//	func main() {
+//	    gen := generator.New(generator.Options{
+//	        Hooks: []generator.Hook{traceHook},
+//	    })
+//	    if err := gen.Generate("./..."); err != nil {
+//	        log.Fatal(err)
+//	    }
+//	}
+//
+//	func traceHook(f *generator.ExtFile, comp *generator.ComponentInfo) error {
+//	    f.Import("log")
+//	    for _, a := range comp.Actions {
+//	        f.Printf("func (c *%s) Trace%s() { log.Print(%q) }\n", comp.TypeName, a.Name, a.Name)
+//	    }
+//	    return nil
+//	}
+//
+// Output from all hooks and extension templates is written to the
+// component's *_hx_ext.go file.
+type Hook func(f *ExtFile, comp *ComponentInfo) error
+
+// ExtFile accumulates declarations and imports for a *_hx_ext.go file.
+// The package clause and import block are written by the generator.
+type ExtFile struct {
+	imports map[string]bool
+	body    bytes.Buffer
+}
+
+// Import adds an import path to the file.
+func (f *ExtFile) Import(path string) {
+	if f.imports == nil {
+		f.imports = make(map[string]bool)
+	}
+	f.imports[path] = true
+}
+
+// Write appends Go declarations to the file body.
+func (f *ExtFile) Write(p []byte) (int, error) {
+	return f.body.Write(p)
+}
+
+// Printf appends formatted Go declarations to the file body.
+func (f *ExtFile) Printf(format string, args ...any) {
+	fmt.Fprintf(&f.body, format, args...)
+}
+
+// source assembles the complete Go file.
+func (f *ExtFile) source(pkgName, sourceFile string) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by hxcmp. DO NOT EDIT.\n// Source: %s\n\n", sourceFile)
+	buf.WriteString("//go:build !hxcmp_ignore\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", pkgName)
+
+	if len(f.imports) > 0 {
+		paths := make([]string, 0, len(f.imports))
+		for path := range f.imports {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+
+		buf.WriteString("import (\n")
+		for _, path := range paths {
+			fmt.Fprintf(&buf, "\t%s\n", strconv.Quote(path))
+		}
+		buf.WriteString(")\n\n")
+	}
+
+	buf.Write(f.body.Bytes())
+	return buf.Bytes()
+}
+
+// hasExtensions reports whether any extension templates or hooks are configured.
+func (g *Generator) hasExtensions() bool {
+	return len(g.opts.Templates) > 0 || len(g.opts.Hooks) > 0
+}
+
+// loadTemplates parses the extension templates from Options.Templates.
+//
+// Templates can call every function available to the built-in template, plus
+// {{import "path"}} to add an import to the generated file.
+func (g *Generator) loadTemplates() error {
+	if g.extTemplates != nil || len(g.opts.Templates) == 0 {
+		return nil
+	}
+
+	for _, path := range g.opts.Templates {
+		text, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("extension template: %w", err)
+		}
+		tmpl, err := template.New(filepath.Base(path)).Funcs(templateFuncs()).Funcs(template.FuncMap{
+			// Replaced per execution; declared here so templates parse
+			"import": func(string) string { return "" },
+		}).Parse(string(text))
+		if err != nil {
+			return fmt.Errorf("extension template: %w", err)
+		}
+		g.extTemplates = append(g.extTemplates, tmpl)
+		g.extTemplateText = append(g.extTemplateText, string(text))
+	}
+
+	return nil
+}
+
+// generateExtensions generates the *_hx_ext.go file for a component from the
+// configured extension templates and hooks. Nothing is written if they
+// produce no output.
+func (g *Generator) generateExtensions(r *pkgRun, pkgName string, comp *ComponentInfo) error {
+	if !g.hasExtensions() {
+		return nil
+	}
+
+	baseName := strings.TrimSuffix(filepath.Base(comp.SourceFile), ".go")
+	outputFile := filepath.Join(r.path, baseName+"_hx_ext.go")
+
+	f := &ExtFile{}
+
+	data := struct {
+		Package   string
+		Component *ComponentInfo
+	}{
+		Package:   pkgName,
+		Component: comp,
+	}
+
+	for _, tmpl := range g.extTemplates {
+		tmpl, err := tmpl.Clone()
+		if err != nil {
+			return err
+		}
+		tmpl.Funcs(template.FuncMap{
+			"import": func(path string) string {
+				f.Import(path)
+				return ""
+			},
+		})
+		if err := tmpl.Execute(&f.body, data); err != nil {
+			return fmt.Errorf("extension template %s: %w", tmpl.Name(), err)
+		}
+		f.body.WriteString("\n")
+	}
+
+	for i, hook := range g.opts.Hooks {
+		if err := hook(f, comp); err != nil {
+			return fmt.Errorf("hook %d: %w", i, err)
+		}
+		f.body.WriteString("\n")
+	}
+
+	if len(bytes.TrimSpace(f.body.Bytes())) == 0 {
+		return nil
+	}
+
+	code := f.source(pkgName, comp.SourceFile)
+	formatted, err := format.Source(code)
+	if err != nil {
+		// Write unformatted for debugging
+		if g.opts.DryRun {
+			return fmt.Errorf("format source: %w", err)
+		}
+		if writeErr := os.WriteFile(outputFile+".unformatted", code, 0644); writeErr == nil {
+			r.logf("  wrote unformatted code to %s.unformatted for debugging", outputFile)
+		}
+		return fmt.Errorf("format source: %w", err)
+	}
+
+	return g.writeFile(r, outputFile, formatted)
+}
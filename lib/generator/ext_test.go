@@ -0,0 +1,87 @@
+package generator
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateExtensions(t *testing.T) {
+	dir := writeManifestPackage(t)
+
+	tmplPath := filepath.Join(t.TempDir(), "audit.tmpl")
+	tmpl := `{{import "log"}}
+{{range .Component.Actions}}
+func (c *{{$.Component.TypeName}}) Audit{{camelToTitle .Name}}() { log.Print("{{.Name}}") }
+{{end}}`
+	if err := os.WriteFile(tmplPath, []byte(tmpl), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	hook := func(f *ExtFile, comp *ComponentInfo) error {
+		f.Import("strings")
+		f.Printf("var %sProps = strings.ToUpper(%q)\n", comp.TypeName, comp.PropsType)
+		return nil
+	}
+
+	g := New(Options{Templates: []string{tmplPath}, Hooks: []Hook{hook}})
+	if err := g.Generate(dir); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	got := readFile(t, filepath.Join(dir, "item_hx_ext.go"))
+	for _, want := range []string{
+		"// Code generated by hxcmp. DO NOT EDIT.",
+		"package components",
+		"import (\n\t\"log\"\n\t\"strings\"\n)",
+		`func (c *ItemView) AuditSave() { log.Print("save") }`,
+		`func (c *ItemView) AuditDelete() { log.Print("delete") }`,
+		`var ItemViewProps = strings.ToUpper("ItemProps")`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("item_hx_ext.go missing %q:\n%s", want, got)
+		}
+	}
+
+	// Regenerating must not pick up the extension file as source
+	if err := g.Generate(dir); err != nil {
+		t.Fatalf("second Generate() error = %v", err)
+	}
+
+	if err := New(Options{}).Clean(dir); err != nil {
+		t.Fatalf("Clean() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "item_hx_ext.go")); !os.IsNotExist(err) {
+		t.Errorf("Clean() left item_hx_ext.go behind")
+	}
+}
+
+func TestGenerateExtensionsEmptyOutput(t *testing.T) {
+	dir := writeManifestPackage(t)
+
+	hook := func(f *ExtFile, comp *ComponentInfo) error { return nil }
+	if err := New(Options{Hooks: []Hook{hook}}).Generate(dir); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "item_hx_ext.go")); !os.IsNotExist(err) {
+		t.Errorf("empty extension output should not write item_hx_ext.go")
+	}
+}
+
+func TestGenerateExtensionsErrors(t *testing.T) {
+	dir := writeManifestPackage(t)
+
+	err := New(Options{Templates: []string{filepath.Join(dir, "missing.tmpl")}}).Generate(dir)
+	if err == nil || !strings.Contains(err.Error(), "extension template") {
+		t.Errorf("missing template: error = %v", err)
+	}
+
+	errHook := errors.New("hook failed")
+	hook := func(f *ExtFile, comp *ComponentInfo) error { return errHook }
+	err = New(Options{Hooks: []Hook{hook}}).Generate(dir)
+	if !errors.Is(err, errHook) || !strings.Contains(err.Error(), "ItemView") {
+		t.Errorf("failing hook: error = %v", err)
+	}
+}
@@ -0,0 +1,246 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"reflect"
+	"strings"
+)
+
+// FormInfo describes a form input struct bound for a handler with the
+// func(ctx, P, F) Result[P] signature.
+type FormInfo struct {
+	TypeName string      // e.g., "TaskForm"
+	Fields   []FormField // Bound fields, in declaration order
+}
+
+// FormField represents a bound field in a form struct.
+type FormField struct {
+	Name   string // Go field name
+	Type   string // Field type as written (e.g., "[]Tag")
+	Key    string // Form key from the form tag (defaults to lowercased name)
+	Layout string // Time layout from the form tag's layout= option
+	Kind   string // Underlying element kind (e.g., "string", "int64", "time.Time")
+	Conv   string // Named element type to convert to, if not Kind (e.g., "Tag")
+	Slice  bool   // Multi-value field (e.g., tags)
+}
+
+// findForms resolves the form structs used by the given actions.
+// Form structs must be declared in the component's package, whose type
+// declarations are decls.
+func (g *Generator) findForms(decls map[string]ast.Expr, actions []ActionInfo) ([]FormInfo, error) {
+	var forms []FormInfo
+	seen := make(map[string]bool)
+
+	for _, action := range actions {
+		if action.Signature != HandlerSigCtxPropsForm || seen[action.FormType] {
+			continue
+		}
+		seen[action.FormType] = true
+
+		form, err := g.findFormStruct(decls, action.FormType)
+		if err != nil {
+			return nil, fmt.Errorf("action %q: %w", action.Name, err)
+		}
+		forms = append(forms, *form)
+	}
+
+	return forms, nil
+}
+
+// findFormStruct parses the fields of a form struct declared in the package
+// whose type declarations are decls.
+func (g *Generator) findFormStruct(decls map[string]ast.Expr, typeName string) (*FormInfo, error) {
+	spec, ok := decls[typeName]
+	if !ok {
+		return nil, fmt.Errorf("form type %s must be declared in the component's package", typeName)
+	}
+	structType, ok := spec.(*ast.StructType)
+	if !ok {
+		return nil, fmt.Errorf("form type %s is not a struct", typeName)
+	}
+
+	form := &FormInfo{TypeName: typeName}
+	for _, field := range structType.Fields.List {
+		if len(field.Names) == 0 {
+			return nil, fmt.Errorf("form type %s: embedded fields are not supported", typeName)
+		}
+
+		var tag reflect.StructTag
+		if field.Tag != nil {
+			tag = reflect.StructTag(strings.Trim(field.Tag.Value, "`"))
+		}
+		key, opts, _ := strings.Cut(tag.Get("form"), ",")
+		if key == "-" {
+			continue
+		}
+
+		for _, name := range field.Names {
+			if !name.IsExported() {
+				continue
+			}
+			ff := FormField{
+				Name: name.Name,
+				Type: g.typeToString(field.Type),
+				Key:  key,
+			}
+			if ff.Key == "" {
+				ff.Key = strings.ToLower(name.Name)
+			}
+			for _, opt := range strings.Split(opts, ",") {
+				if layout, ok := strings.CutPrefix(opt, "layout="); ok {
+					ff.Layout = layout
+				}
+			}
+
+			elem := field.Type
+			if arr, ok := elem.(*ast.ArrayType); ok && arr.Len == nil {
+				ff.Slice = true
+				elem = arr.Elt
+			}
+			ff.Kind, ff.Conv = g.resolveFormKind(elem, decls)
+			if ff.Kind == "" || (ff.Slice && (ff.Kind == "bool" || ff.Kind == "time.Time")) {
+				return nil, fmt.Errorf("form field %s.%s: unsupported type %s", typeName, ff.Name, ff.Type)
+			}
+
+			form.Fields = append(form.Fields, ff)
+		}
+	}
+
+	return form, nil
+}
+
+// resolveFormKind returns the underlying bindable kind of a field element
+// type and, for named types like "type Tag string", the type to convert to.
+func (g *Generator) resolveFormKind(expr ast.Expr, decls map[string]ast.Expr) (kind, conv string) {
+	name := g.typeToString(expr)
+	if isScalarType(name) {
+		return name, ""
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		if underlying, ok := decls[ident.Name]; ok {
+			if u := g.typeToString(underlying); isScalarType(u) && u != "time.Time" {
+				return u, ident.Name
+			}
+		}
+	}
+	return "", ""
+}
+
+// packageTypeDecls maps type names declared in pkg to their type expressions.
+func packageTypeDecls(pkg *ast.Package) map[string]ast.Expr {
+	decls := make(map[string]ast.Expr)
+	for _, file := range pkg.Files {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				if typeSpec, ok := spec.(*ast.TypeSpec); ok {
+					decls[typeSpec.Name.Name] = typeSpec.Type
+				}
+			}
+		}
+	}
+	return decls
+}
+
+// intBits returns the bit size argument for a sized integer or float kind.
+func intBits(kind string) int {
+	switch kind {
+	case "int8", "uint8":
+		return 8
+	case "int16", "uint16":
+		return 16
+	case "int32", "uint32", "float32":
+		return 32
+	case "int64", "uint64", "float64":
+		return 64
+	default:
+		return 0
+	}
+}
+
+// bindFieldCode generates the code that binds a form field from the
+// hxcmp.FormBinder b into the form struct f.
+func bindFieldCode(f FormField) string {
+	target := f.Kind
+	if f.Conv != "" {
+		target = f.Conv
+	}
+
+	// Binder method and its natural Go result type for the kind
+	var method, args, natural string
+	switch f.Kind {
+	case "string":
+		method, natural = "String", "string"
+		args = fmt.Sprintf("%q", f.Key)
+	case "bool":
+		method, natural = "Bool", "bool"
+		args = fmt.Sprintf("%q", f.Key)
+	case "time.Time":
+		method, natural = "Time", "time.Time"
+		args = fmt.Sprintf("%q, %q", f.Key, f.Layout)
+	case "int", "int8", "int16", "int32", "int64":
+		method, natural = "Int", "int64"
+		args = fmt.Sprintf("%q, %d", f.Key, intBits(f.Kind))
+	case "uint", "uint8", "uint16", "uint32", "uint64":
+		method, natural = "Uint", "uint64"
+		args = fmt.Sprintf("%q, %d", f.Key, intBits(f.Kind))
+	case "float32", "float64":
+		method, natural = "Float", "float64"
+		args = fmt.Sprintf("%q, %d", f.Key, intBits(f.Kind))
+	}
+
+	if f.Slice {
+		if target == natural {
+			return fmt.Sprintf(`f.%s = b.%ss(%s)`, f.Name, method, args)
+		}
+		return fmt.Sprintf(`for _, v := range b.%ss(%s) { f.%s = append(f.%s, %s(v)) }`, method, args, f.Name, f.Name, target)
+	}
+
+	if target == natural {
+		return fmt.Sprintf(`f.%s = b.%s(%s)`, f.Name, method, args)
+	}
+	return fmt.Sprintf(`f.%s = %s(b.%s(%s))`, f.Name, target, method, args)
+}
+
+// formValueCode generates the code that adds a form struct field from f to
+// the url.Values v, in the form bindFieldCode reads back.
+func formValueCode(f FormField) string {
+	// format converts the expression x to a string
+	format := func(x string) string {
+		switch f.Kind {
+		case "time.Time":
+			layout := "time.RFC3339"
+			if f.Layout != "" {
+				layout = fmt.Sprintf("%q", f.Layout)
+			}
+			return x + ".Format(" + layout + ")"
+		case "int", "int8", "int16", "int32", "int64":
+			return "strconv.FormatInt(int64(" + x + "), 10)"
+		case "uint", "uint8", "uint16", "uint32", "uint64":
+			return "strconv.FormatUint(uint64(" + x + "), 10)"
+		case "float32", "float64":
+			return fmt.Sprintf("strconv.FormatFloat(float64(%s), 'g', -1, %d)", x, intBits(f.Kind))
+		default:
+			return "string(" + x + ")"
+		}
+	}
+
+	if f.Slice {
+		return fmt.Sprintf(`for _, x := range f.%s { v.Add(%q, %s) }`, f.Name, f.Key, format("x"))
+	}
+
+	// Zero times and false bools are left out, as a browser would for an
+	// empty date input or an unchecked box
+	switch f.Kind {
+	case "bool":
+		return fmt.Sprintf(`if f.%s { v.Set(%q, "true") }`, f.Name, f.Key)
+	case "time.Time":
+		return fmt.Sprintf(`if !f.%s.IsZero() { v.Set(%q, %s) }`, f.Name, f.Key, format("f."+f.Name))
+	}
+	return fmt.Sprintf(`v.Set(%q, %s)`, f.Key, format("f."+f.Name))
+}
@@ -0,0 +1,93 @@
+package generator
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// TestGeneratedCode generates code for the fixture package in testdata and
+// runs the fixture's own tests against it. This verifies that generated code
+// compiles and behaves correctly end to end through the Registry.
+func TestGeneratedCode(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping generated code build in short mode")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not available")
+	}
+
+	root, err := filepath.Abs(filepath.Join("..", ".."))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	copyDir(t, filepath.Join("testdata", "fixture"), dir)
+
+	goMod := `module fixture
+
+go 1.23.0
+
+require (
+	github.com/a-h/templ v0.3.977
+	github.com/pthm/hxcmp v0.0.0
+)
+
+replace github.com/pthm/hxcmp => ` + root + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sum, err := os.ReadFile(filepath.Join(root, "go.sum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.sum"), sum, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := Options{
+		Templates: []string{filepath.Join("testdata", "ext", "trace.tmpl")},
+		Hooks:     []Hook{actionCountHook},
+	}
+	if err := New(opts).Generate(dir); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	cmd := exec.Command(goBin, "test", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go test in generated fixture failed: %v\n%s", err, out)
+	}
+}
+
+// actionCountHook is an extension hook exercised by the fixture tests.
+func actionCountHook(f *ExtFile, comp *ComponentInfo) error {
+	f.Printf("// ActionCount returns the number of registered actions.\n")
+	f.Printf("func (c *%s) ActionCount() int { return %d }\n", comp.TypeName, len(comp.Actions))
+	return nil
+}
+
+// copyDir copies the regular files in src to dst.
+func copyDir(t *testing.T, src, dst string) {
+	t.Helper()
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(src, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dst, entry.Name()), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -1,48 +1,152 @@
 package generator
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"log"
 	"os"
 	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
 	"strings"
+	"text/template"
 )
 
 // Options configures the generator.
 type Options struct {
 	DryRun bool
+
+	// Logger receives human-readable progress output ("generating ...").
+	// Defaults to standard output.
+	Logger Logger
+
+	// Templates are paths to extra text/template files rendered for every
+	// component. They receive the same data as the built-in template
+	// ({{.Package}} and {{.Component}}, a *ComponentInfo) and produce Go
+	// declarations; use {{import "path"}} to add imports.
+	Templates []string
+
+	// Hooks generate extra code for every component from Go. They run after
+	// Templates, and are registered by a custom main. Hooks are called
+	// concurrently for different packages and must be safe for that.
+	Hooks []Hook
+
+	// Workers is the number of packages generated concurrently.
+	// Zero means runtime.GOMAXPROCS(0).
+	Workers int
+
+	// Force regenerates every package, even when its inputs are unchanged
+	// since the last run.
+	Force bool
 }
 
 // Generator generates hxcmp code.
 type Generator struct {
-	opts Options
-	fset *token.FileSet
+	opts            Options
+	logger          Logger
+	fset            *token.FileSet
+	extTemplates    []*template.Template // Parsed Options.Templates
+	extTemplateText []string             // Contents of Options.Templates, for hashing
 }
 
 // New creates a new generator.
 func New(opts Options) *Generator {
+	logger := opts.Logger
+	if logger == nil {
+		logger = log.New(os.Stdout, "", 0)
+	}
 	return &Generator{
-		opts: opts,
-		fset: token.NewFileSet(),
+		opts:   opts,
+		logger: logger,
+		fset:   token.NewFileSet(),
 	}
 }
 
 // Generate generates code for the given package patterns.
+//
+// It is shorthand for GenerateReport for callers that only need the error.
 func (g *Generator) Generate(patterns ...string) error {
+	_, err := g.GenerateReport(patterns...)
+	return err
+}
+
+// GenerateReport generates code for the given package patterns and returns
+// a report of every package, component, and file, with all diagnostics.
+//
+// Packages are generated concurrently. Progress output is sent to
+// Options.Logger and the report is assembled in package order, so both are
+// deterministic. A failing package doesn't stop the others; the returned
+// error joins the errors of every failed package.
+func (g *Generator) GenerateReport(patterns ...string) (*Report, error) {
+	report := &Report{
+		DryRun:      g.opts.DryRun,
+		Packages:    []PackageReport{},
+		Diagnostics: []Diagnostic{},
+	}
+
+	if err := g.loadTemplates(); err != nil {
+		return report, err
+	}
+
 	packages, err := g.findPackages(patterns)
 	if err != nil {
-		return err
+		return report, err
 	}
 
-	for _, pkg := range packages {
-		if err := g.generatePackage(pkg); err != nil {
-			return fmt.Errorf("package %s: %w", pkg, err)
+	runs := make([]*pkgRun, len(packages))
+	for i, pkg := range packages {
+		runs[i] = newPkgRun(pkg)
+	}
+
+	workers := g.opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	jobs := make(chan *pkgRun)
+	for w := 0; w < workers; w++ {
+		go func() {
+			for r := range jobs {
+				if r.err = g.generatePackage(r); r.err != nil {
+					r.diagnose(SeverityError, "", r.err.Error())
+				}
+				close(r.done)
+			}
+		}()
+	}
+	go func() {
+		for _, r := range runs {
+			jobs <- r
 		}
+		close(jobs)
+	}()
+
+	for _, r := range runs {
+		<-r.done
 	}
 
-	return nil
+	// Listeners are checked against the events emitted by every package
+	g.checkListeners(runs)
+
+	// Report in package order, regardless of completion order
+	var errs []error
+	for _, r := range runs {
+		for _, line := range r.lines {
+			g.logger.Printf("%s", line)
+		}
+		if r.err != nil {
+			errs = append(errs, fmt.Errorf("package %s: %w", r.path, r.err))
+		}
+		report.Packages = append(report.Packages, r.report)
+		report.Diagnostics = append(report.Diagnostics, r.report.Diagnostics...)
+	}
+
+	return report, errors.Join(errs...)
 }
 
 // Clean removes generated files for the given package patterns.
@@ -86,16 +190,8 @@ func (g *Generator) findPackages(patterns []string) ([]string, error) {
 					return filepath.SkipDir
 				}
 
-				// Check if directory contains Go files
-				entries, err := os.ReadDir(path)
-				if err != nil {
-					return nil
-				}
-				for _, entry := range entries {
-					if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".go") && !strings.HasSuffix(entry.Name(), "_test.go") {
-						packages = append(packages, path)
-						break
-					}
+				if hasGoFiles(path) {
+					packages = append(packages, path)
 				}
 				return nil
 			})
@@ -111,39 +207,142 @@ func (g *Generator) findPackages(patterns []string) ([]string, error) {
 	return packages, nil
 }
 
-// generatePackage generates code for a single package.
-func (g *Generator) generatePackage(pkgPath string) error {
-	// Parse all Go files in the package
-	pkgs, err := parser.ParseDir(g.fset, pkgPath, func(info os.FileInfo) bool {
+// parsePackage parses the Go files in a package directory, skipping test
+// files and generated files.
+func (g *Generator) parsePackage(pkgPath string) (map[string]*ast.Package, error) {
+	return parser.ParseDir(g.fset, pkgPath, func(info os.FileInfo) bool {
 		name := info.Name()
 		// Skip test files and generated files
-		return !strings.HasSuffix(name, "_test.go") && !strings.HasSuffix(name, "_hx.go")
+		return isInputFile(name)
 	}, parser.ParseComments)
+}
+
+// generatePackage generates code for a single package.
+//
+// A package whose inputs hash to the value recorded by the last run is
+// skipped: it is only parsed to list its components in the report.
+func (g *Generator) generatePackage(r *pkgRun) error {
+	pkgPath := r.path
+
+	if !g.opts.Force {
+		hash, err := g.inputsHash(pkgPath)
+		if err != nil {
+			return err
+		}
+		if hash != "" && hash == recordedHash(pkgPath) {
+			r.logf("skipping %s (unchanged)", pkgPath)
+			r.report.Skipped = true
+			r.emits, r.listens = recordedEvents(pkgPath)
+			entries, err := os.ReadDir(pkgPath)
+			if err != nil {
+				return err
+			}
+			for _, entry := range entries {
+				if isGeneratedFile(entry.Name()) {
+					r.file(filepath.Join(pkgPath, entry.Name()), FileSkipped)
+				}
+			}
+			return g.reportComponents(r)
+		}
+	}
+
+	// Parse all Go files in the package
+	pkgs, err := g.parsePackage(pkgPath)
 	if err != nil {
 		return err
 	}
 
-	for pkgName, pkg := range pkgs {
-		components := g.findComponents(pkg)
+	pkgNames := make([]string, 0, len(pkgs))
+	for pkgName := range pkgs {
+		pkgNames = append(pkgNames, pkgName)
+	}
+	sort.Strings(pkgNames)
+
+	var withComponents []string
+	events := make(map[string]*packageEvents)
+	for _, pkgName := range pkgNames {
+		components, err := g.findComponents(pkgs[pkgName])
+		if err != nil {
+			return err
+		}
 		if len(components) == 0 {
 			continue
 		}
+		r.report.Name = pkgName
+		withComponents = append(withComponents, pkgName)
 
-		// Generate helpers file once per package
-		if err := g.generateHelpers(pkgPath, pkgName); err != nil {
+		if events[pkgName], err = collectEvents(components); err != nil {
 			return err
 		}
+		for _, ev := range events[pkgName].Emits {
+			r.emits = append(r.emits, ev.Name)
+		}
 
 		for _, comp := range components {
-			if err := g.generateComponent(pkgPath, pkgName, comp); err != nil {
+			r.report.Components = append(r.report.Components, newComponentReport(comp))
+			for _, ev := range comp.Listens {
+				r.listens = append(r.listens, eventUse{event: ev.Name, file: comp.SourceFile})
+			}
+			for _, w := range comp.Warnings {
+				r.diagnose(SeverityWarning, comp.SourceFile, w)
+			}
+
+			if err := g.generateComponent(r, pkgName, comp); err != nil {
 				return err
 			}
+			if err := g.generateExtensions(r, pkgName, comp); err != nil {
+				return fmt.Errorf("%s: %w", comp.TypeName, err)
+			}
+		}
+	}
+
+	// hx_helpers.go is kept, and rewritten below, if the package still has
+	// components
+	if err := g.removeStale(r, len(withComponents) > 0); err != nil {
+		return err
+	}
+
+	// Generate helpers file once per package, last, so the recorded hash
+	// covers the generated files now present
+	for _, pkgName := range withComponents {
+		if err := g.generateHelpers(r, pkgName, events[pkgName]); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
+// reportComponents lists the components of a package skipped as unchanged
+// in its report, without generating anything.
+func (g *Generator) reportComponents(r *pkgRun) error {
+	pkgs, err := g.parsePackage(r.path)
+	if err != nil {
+		return err
+	}
+
+	pkgNames := make([]string, 0, len(pkgs))
+	for pkgName := range pkgs {
+		pkgNames = append(pkgNames, pkgName)
+	}
+	sort.Strings(pkgNames)
+
+	for _, pkgName := range pkgNames {
+		components, err := g.findComponents(pkgs[pkgName])
+		if err != nil {
+			return err
+		}
+		if len(components) == 0 {
+			continue
+		}
+		r.report.Name = pkgName
+		for _, comp := range components {
+			r.report.Components = append(r.report.Components, newComponentReport(comp))
+		}
+	}
+	return nil
+}
+
 // cleanPackage removes generated files from a package.
 func (g *Generator) cleanPackage(pkgPath string) error {
 	entries, err := os.ReadDir(pkgPath)
@@ -158,10 +357,11 @@ func (g *Generator) cleanPackage(pkgPath string) error {
 		if entry.IsDir() {
 			continue
 		}
-		// Remove both *_hx.go and hx_helpers.go files
-		if strings.HasSuffix(entry.Name(), "_hx.go") || entry.Name() == "hx_helpers.go" {
-			path := filepath.Join(pkgPath, entry.Name())
-			fmt.Printf("removing %s\n", path)
+		// Remove *_hx.go, *_hx_test.go, *_hx_ext.go, and hx_helpers.go files
+		name := entry.Name()
+		if isGeneratedFile(name) {
+			path := filepath.Join(pkgPath, name)
+			g.logger.Printf("removing %s", path)
 			if !g.opts.DryRun {
 				if err := os.Remove(path); err != nil {
 					return err
@@ -176,20 +376,27 @@ func (g *Generator) cleanPackage(pkgPath string) error {
 // ComponentInfo holds information about a discovered component.
 type ComponentInfo struct {
 	SourceFile   string
+	SourceHash   string       // SHA-256 of the source file, e.g. "sha256:9f86d0..."
 	TypeName     string       // e.g., "FileViewer"
 	PropsType    string       // e.g., "Props"
 	Props        []PropField  // Parsed props fields
 	Actions      []ActionInfo // Registered actions
+	Forms        []FormInfo   // Form input structs used by actions
+	Emits        []EventInfo  // Events declared with Emits
+	Listens      []EventInfo  // Events declared with Listens
+	Warnings     []string     // Problems that don't stop generation
 	ComponentNew string       // The name passed to hxcmp.New[P]("name")
+	Sensitive    bool         // Marked with .Sensitive(), so props are encrypted
 }
 
 // PropField represents a field in the Props struct.
 type PropField struct {
-	Name      string
-	Type      string
-	Tag       string // The hx tag value
-	OmitEmpty bool
-	Exclude   bool // hx:"-"
+	Name        string
+	Type        string
+	Tag         string // The hx tag value
+	OmitEmpty   bool
+	Exclude     bool            // hx:"-"
+	Constraints PropConstraints // Enforced by the generated HXDecode
 }
 
 // HandlerSignature represents the detected handler signature type.
@@ -202,21 +409,52 @@ const (
 	HandlerSigCtxPropsRequest
 	// HandlerSigCtxPropsWriter: func(ctx, P, http.ResponseWriter) Result[P]
 	HandlerSigCtxPropsWriter
+	// HandlerSigCtxPropsForm: func(ctx, P, F) Result[P] where F is a form struct
+	HandlerSigCtxPropsForm
+	// HandlerSigCtxPropsWriterRequest: func(ctx, P, http.ResponseWriter, *http.Request) Result[P]
+	HandlerSigCtxPropsWriterRequest
+	// HandlerSigRuntime: the handler's declaration is not visible to the
+	// generator, so generated dispatch selects the signature with a type switch
+	HandlerSigRuntime
+	// HandlerSigUnsupported: the handler matches none of the signatures
+	// above; generation fails for actions that use it
+	HandlerSigUnsupported
+)
+
+// HandlerKind describes how an action's handler is written in the constructor.
+type HandlerKind string
+
+const (
+	// HandlerMethod is a method of the component: c.handleEdit
+	HandlerMethod HandlerKind = "method"
+	// HandlerFunc is a package-level function: handleEdit or handlers.Edit
+	HandlerFunc HandlerKind = "func"
+	// HandlerLiteral is a function literal: func(ctx context.Context, p Props) hxcmp.Result[Props] {...}
+	HandlerLiteral HandlerKind = "literal"
+	// HandlerValue is a method value on another receiver: c.store.HandleEdit
+	HandlerValue HandlerKind = "value"
 )
 
 // ActionInfo represents a registered action.
 type ActionInfo struct {
-	Name      string           // Action name (e.g., "edit")
-	Method    string           // HTTP method (defaults to POST)
-	Handler   string           // Handler method name
-	Signature HandlerSignature // Detected handler signature
+	Name        string           // Action name (e.g., "edit")
+	Method      string           // HTTP method (defaults to POST)
+	Handler     string           // Handler expression (e.g., "handleEdit" or "c.store.Edit"); empty for literals
+	HandlerKind HandlerKind      // How the handler is written
+	Signature   HandlerSignature // Detected handler signature
+	FormType    string           // Form struct type for HandlerSigCtxPropsForm
+
+	params string // Parameter types of a HandlerSigUnsupported handler
 }
 
 // findComponents finds all component types in a package.
-func (g *Generator) findComponents(pkg *ast.Package) []*ComponentInfo {
+func (g *Generator) findComponents(pkg *ast.Package) ([]*ComponentInfo, error) {
 	var components []*ComponentInfo
+	decls := packageTypeDecls(pkg)
 
 	for filename, file := range pkg.Files {
+		var sourceHash string
+
 		for _, decl := range file.Decls {
 			// Look for type declarations
 			genDecl, ok := decl.(*ast.GenDecl)
@@ -241,25 +479,76 @@ func (g *Generator) findComponents(pkg *ast.Package) []*ComponentInfo {
 					continue
 				}
 
+				if sourceHash == "" {
+					src, err := os.ReadFile(filename)
+					if err != nil {
+						return nil, err
+					}
+					sum := sha256.Sum256(src)
+					sourceHash = "sha256:" + hex.EncodeToString(sum[:])
+				}
+
 				comp := &ComponentInfo{
 					SourceFile:   filename,
+					SourceHash:   sourceHash,
 					TypeName:     typeSpec.Name.Name,
 					PropsType:    propsType,
 					ComponentNew: componentNew,
 				}
 
 				// Find the Props struct in the same file
-				comp.Props = g.findPropsFields(file, propsType)
+				props, err := g.findPropsFields(file, propsType)
+				if err != nil {
+					return nil, err
+				}
+				comp.Props = props
 
 				// Find action registrations
-				comp.Actions = g.findActions(file, typeSpec.Name.Name)
+				handlers := g.findHandlers(file, typeSpec.Name.Name, decls)
+				comp.Actions = g.findActions(file, handlers, decls)
+				g.resolveFuncHandlers(pkg, comp.Actions, decls)
+				for _, a := range comp.Actions {
+					switch {
+					case a.Signature == HandlerSigUnsupported:
+						handler := a.Handler
+						if a.HandlerKind == HandlerLiteral {
+							handler = "function literal"
+						}
+						return nil, fmt.Errorf("%s: action %q: unsupported handler signature func%s for %s; %s",
+							comp.TypeName, a.Name, a.params, handler, supportedSignatures)
+					case a.HandlerKind == HandlerMethod && !hasHandler(handlers, a.Handler):
+						comp.Warnings = append(comp.Warnings, fmt.Sprintf(
+							"action %q: handler %s is not a method of %s in %s; assuming %s",
+							a.Name, a.Handler, comp.TypeName, filepath.Base(filename), a.Signature))
+					case a.Signature == HandlerSigRuntime:
+						comp.Warnings = append(comp.Warnings, fmt.Sprintf(
+							"action %q: handler %s is not a function declared in this package; its signature is checked when the action is served",
+							a.Name, a.Handler))
+					}
+				}
+
+				// Resolve form input structs used by actions
+				forms, err := g.findForms(decls, comp.Actions)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", comp.TypeName, err)
+				}
+				comp.Forms = forms
+
+				comp.Sensitive = findSensitive(file)
+
+				// Find event declarations
+				comp.Emits, comp.Listens, err = g.findEvents(file)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", comp.TypeName, err)
+				}
 
 				components = append(components, comp)
 			}
 		}
 	}
 
-	return components
+	sortComponents(components)
+	return components, nil
 }
 
 // findEmbeddedComponent checks if a struct embeds *hxcmp.Component[P].
@@ -310,8 +599,9 @@ func (g *Generator) findEmbeddedComponent(structType *ast.StructType) (propsType
 	return "", ""
 }
 
-// findPropsFields finds the fields of a Props struct.
-func (g *Generator) findPropsFields(file *ast.File, propsTypeName string) []PropField {
+// findPropsFields finds the fields of a Props struct. Tags with invalid
+// options or constraints are errors at the field's position.
+func (g *Generator) findPropsFields(file *ast.File, propsTypeName string) ([]PropField, error) {
 	var fields []PropField
 
 	for _, decl := range file.Decls {
@@ -345,7 +635,14 @@ func (g *Generator) findPropsFields(file *ast.File, propsTypeName string) []Prop
 					// Parse hx tag
 					if field.Tag != nil {
 						tag := strings.Trim(field.Tag.Value, "`")
-						pf.Tag, pf.OmitEmpty, pf.Exclude = parseHXTag(tag)
+						var err error
+						pf.Tag, pf.OmitEmpty, pf.Exclude, pf.Constraints, err = parseHXTag(tag)
+						if err == nil {
+							err = pf.Constraints.check(pf)
+						}
+						if err != nil {
+							return nil, fmt.Errorf("%s: %s.%s: %w", g.fset.Position(name.Pos()), propsTypeName, name.Name, err)
+						}
 					}
 
 					// Auto-detection for untagged fields
@@ -363,19 +660,20 @@ func (g *Generator) findPropsFields(file *ast.File, propsTypeName string) []Prop
 		}
 	}
 
-	return fields
+	return fields, nil
 }
 
 // findActions finds action registrations in the component's New function.
-func (g *Generator) findActions(file *ast.File, typeName string) []ActionInfo {
+// handlers are the component's methods, from findHandlers, and decls holds
+// the package's type declarations, which form types must be.
+func (g *Generator) findActions(file *ast.File, handlers map[string]handlerDecl, decls map[string]ast.Expr) []ActionInfo {
 	// Use a map to deduplicate actions by name.
 	// When an action is registered with .Method(), it may be found twice
 	// (once via the chain, once via the inner c.Action call).
 	// Keep the version with a custom method over the default POST.
 	actionMap := make(map[string]ActionInfo)
 
-	// Build a map of handler method signatures for later lookup
-	handlerSigs := g.findHandlerSignatures(file, typeName)
+	imports := fileImports(file)
 
 	// Look for function declarations
 	for _, decl := range file.Decls {
@@ -387,11 +685,13 @@ func (g *Generator) findActions(file *ast.File, typeName string) []ActionInfo {
 		// Look for c.Action(...) calls, potentially chained with .Method(...)
 		ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
 			if callExpr, ok := n.(*ast.CallExpr); ok {
-				action := g.extractActionFromCall(callExpr)
+				action := g.extractActionFromCall(callExpr, imports, decls)
 				if action != nil {
 					// Look up the handler signature
-					if sig, ok := handlerSigs[action.Handler]; ok {
-						action.Signature = sig
+					if h, ok := handlers[action.Handler]; ok && action.HandlerKind == HandlerMethod {
+						action.Signature = h.signature
+						action.FormType = h.formType
+						action.params = h.params
 					}
 					// Keep the version with custom method over default POST
 					existing, exists := actionMap[action.Name]
@@ -404,19 +704,113 @@ func (g *Generator) findActions(file *ast.File, typeName string) []ActionInfo {
 		})
 	}
 
-	// Convert map to slice
+	// Convert map to slice, sorted so output is the same on every run
 	var actions []ActionInfo
 	for _, action := range actionMap {
 		actions = append(actions, action)
 	}
+	sort.Slice(actions, func(i, j int) bool {
+		return actions[i].Name < actions[j].Name
+	})
 
 	return actions
 }
 
-// findHandlerSignatures finds all method signatures for a component type.
-// Returns a map of method name to signature type.
-func (g *Generator) findHandlerSignatures(file *ast.File, typeName string) map[string]HandlerSignature {
-	sigs := make(map[string]HandlerSignature)
+// resolveFuncHandlers detects the signatures of handlers that are
+// package-level functions declared in pkg. Handlers declared elsewhere are
+// left as HandlerSigRuntime.
+func (g *Generator) resolveFuncHandlers(pkg *ast.Package, actions []ActionInfo, decls map[string]ast.Expr) {
+	for i := range actions {
+		a := &actions[i]
+		if a.HandlerKind != HandlerFunc || strings.Contains(a.Handler, ".") {
+			continue
+		}
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				funcDecl, ok := decl.(*ast.FuncDecl)
+				if ok && funcDecl.Recv == nil && funcDecl.Name.Name == a.Handler {
+					h := g.funcSignature(funcDecl.Type, decls)
+					a.Signature, a.FormType, a.params = h.signature, h.formType, h.params
+				}
+			}
+		}
+	}
+}
+
+// funcSignature detects the handler signature of a function type, with its
+// form type for HandlerSigCtxPropsForm and its parameter types for
+// HandlerSigUnsupported.
+func (g *Generator) funcSignature(fn *ast.FuncType, decls map[string]ast.Expr) handlerDecl {
+	var params []*ast.Field
+	if fn.Params != nil {
+		params = fn.Params.List
+	}
+	h := handlerDecl{signature: g.detectHandlerSignature(params, decls)}
+	switch h.signature {
+	case HandlerSigCtxPropsForm:
+		h.formType = g.typeToString(params[len(params)-1].Type)
+	case HandlerSigUnsupported:
+		types := make([]string, 0, len(params))
+		for _, expr := range paramTypes(params) {
+			types = append(types, g.typeToString(expr))
+		}
+		h.params = "(" + strings.Join(types, ", ") + ")"
+	}
+	return h
+}
+
+// hasHandler reports whether name is a handler method in handlers.
+func hasHandler(handlers map[string]handlerDecl, name string) bool {
+	_, ok := handlers[name]
+	return ok
+}
+
+// findSensitive reports whether a function in file marks the component
+// sensitive with a .Sensitive() call.
+func findSensitive(file *ast.File) bool {
+	found := false
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Body == nil {
+			continue
+		}
+		ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+			if call, ok := n.(*ast.CallExpr); ok && len(call.Args) == 0 {
+				if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Sensitive" {
+					found = true
+				}
+			}
+			return !found
+		})
+	}
+	return found
+}
+
+// fileImports returns the names that refer to imported packages in file.
+func fileImports(file *ast.File) map[string]bool {
+	names := make(map[string]bool)
+	for _, imp := range file.Imports {
+		if imp.Name != nil {
+			names[imp.Name.Name] = true
+			continue
+		}
+		path := strings.Trim(imp.Path.Value, `"`)
+		names[path[strings.LastIndex(path, "/")+1:]] = true
+	}
+	return names
+}
+
+// handlerDecl describes a handler method declared on a component type.
+type handlerDecl struct {
+	signature HandlerSignature
+	formType  string // Third parameter type for HandlerSigCtxPropsForm
+	params    string // Parameter types for HandlerSigUnsupported
+}
+
+// findHandlers finds all methods declared on a component type and detects
+// their handler signatures.
+func (g *Generator) findHandlers(file *ast.File, typeName string, decls map[string]ast.Expr) map[string]handlerDecl {
+	handlers := make(map[string]handlerDecl)
 
 	for _, decl := range file.Decls {
 		funcDecl, ok := decl.(*ast.FuncDecl)
@@ -435,19 +829,21 @@ func (g *Generator) findHandlerSignatures(file *ast.File, typeName string) map[s
 		}
 
 		// Detect signature based on parameter count and types
-		if funcDecl.Type.Params == nil {
-			continue
-		}
-		params := funcDecl.Type.Params.List
-		sig := g.detectHandlerSignature(params)
-		sigs[funcDecl.Name.Name] = sig
+		handlers[funcDecl.Name.Name] = g.funcSignature(funcDecl.Type, decls)
 	}
 
-	return sigs
+	return handlers
 }
 
-// detectHandlerSignature determines the signature type from function parameters.
-func (g *Generator) detectHandlerSignature(params []*ast.Field) HandlerSignature {
+// supportedSignatures lists the handler signatures in generation errors.
+const supportedSignatures = "handlers take (ctx, props), (ctx, props, *http.Request), " +
+	"(ctx, props, http.ResponseWriter), (ctx, props, w, r), or (ctx, props, form) " +
+	"with a form struct declared in the component's package"
+
+// detectHandlerSignature determines the signature type from function
+// parameters. decls holds the package's type declarations: a third
+// parameter is a form only when it names a struct declared there.
+func (g *Generator) detectHandlerSignature(params []*ast.Field, decls map[string]ast.Expr) HandlerSignature {
 	paramCount := countParams(params)
 
 	// (ctx, props) = 2 params
@@ -455,19 +851,34 @@ func (g *Generator) detectHandlerSignature(params []*ast.Field) HandlerSignature
 		return HandlerSigCtxProps
 	}
 
-	// (ctx, props, request/writer) = 3 params
-	if paramCount >= 3 && len(params) >= 3 {
-		thirdType := g.typeToString(params[2].Type)
-		switch thirdType {
+	// (ctx, props, request/writer/form) = 3 params
+	if paramCount == 3 {
+		// The third parameter is always declared by the last field, even when
+		// names are grouped (e.g., "ctx context.Context, props Props, f Form").
+		third := params[len(params)-1].Type
+		switch g.typeToString(third) {
 		case "*http.Request":
 			return HandlerSigCtxPropsRequest
 		case "http.ResponseWriter":
 			return HandlerSigCtxPropsWriter
 		}
+		// Otherwise it must be a form input struct of the package
+		if ident, ok := third.(*ast.Ident); ok {
+			if _, ok := decls[ident.Name].(*ast.StructType); ok {
+				return HandlerSigCtxPropsForm
+			}
+		}
+	}
+
+	// (ctx, props, w, r) = 4 params
+	if paramCount == 4 {
+		exprs := paramTypes(params)
+		if g.typeToString(exprs[2]) == "http.ResponseWriter" && g.typeToString(exprs[3]) == "*http.Request" {
+			return HandlerSigCtxPropsWriterRequest
+		}
 	}
 
-	// Default to ctx, props, request for backwards compatibility
-	return HandlerSigCtxPropsRequest
+	return HandlerSigUnsupported
 }
 
 // countParams counts the actual number of parameters in a field list.
@@ -484,15 +895,34 @@ func countParams(params []*ast.Field) int {
 	return count
 }
 
+// paramTypes returns the type of each parameter, expanding grouped names.
+func paramTypes(params []*ast.Field) []ast.Expr {
+	var exprs []ast.Expr
+	for _, p := range params {
+		n := len(p.Names)
+		if n == 0 {
+			n = 1
+		}
+		for i := 0; i < n; i++ {
+			exprs = append(exprs, p.Type)
+		}
+	}
+	return exprs
+}
+
 // extractActionFromCall extracts action info from a call expression.
 // Handles both c.Action("name", handler) and c.Action("name", handler).Method(method) chains.
-func (g *Generator) extractActionFromCall(callExpr *ast.CallExpr) *ActionInfo {
+// imports holds the names of the file's imported packages, and decls the
+// package's type declarations.
+func (g *Generator) extractActionFromCall(callExpr *ast.CallExpr, imports map[string]bool, decls map[string]ast.Expr) *ActionInfo {
 	// Check if this is a .Method(...) call chained on Action
 	if selExpr, ok := callExpr.Fun.(*ast.SelectorExpr); ok {
 		if selExpr.Sel.Name == "Method" {
-			// This is .Method(...) - find the underlying Action call
+			// This is .Method(...) - find the underlying Action call, which
+			// may be followed by other builder calls such as .Use(...)
 			if innerCall, ok := selExpr.X.(*ast.CallExpr); ok {
-				action := g.extractActionCall(innerCall)
+				innerCall = actionCallOf(innerCall)
+				action := g.extractActionCall(innerCall, imports, decls)
 				if action != nil {
 					// Extract the method from .Method(...) args
 					if len(callExpr.Args) >= 1 {
@@ -506,15 +936,31 @@ func (g *Generator) extractActionFromCall(callExpr *ast.CallExpr) *ActionInfo {
 
 		// Check if this is c.Action(...) directly
 		if selExpr.Sel.Name == "Action" {
-			return g.extractActionCall(callExpr)
+			return g.extractActionCall(callExpr, imports, decls)
 		}
 	}
 
 	return nil
 }
 
+// actionCallOf returns the c.Action(...) call at the start of a chain of
+// ActionBuilder calls, or call itself if it doesn't end in one.
+func actionCallOf(call *ast.CallExpr) *ast.CallExpr {
+	for {
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name == "Action" {
+			return call
+		}
+		inner, ok := sel.X.(*ast.CallExpr)
+		if !ok {
+			return call
+		}
+		call = inner
+	}
+}
+
 // extractActionCall extracts action info from a c.Action("name", handler) call.
-func (g *Generator) extractActionCall(callExpr *ast.CallExpr) *ActionInfo {
+func (g *Generator) extractActionCall(callExpr *ast.CallExpr, imports map[string]bool, decls map[string]ast.Expr) *ActionInfo {
 	selExpr, ok := callExpr.Fun.(*ast.SelectorExpr)
 	if !ok || selExpr.Sel.Name != "Action" {
 		return nil
@@ -536,9 +982,34 @@ func (g *Generator) extractActionCall(callExpr *ast.CallExpr) *ActionInfo {
 		Method: "POST", // Default
 	}
 
-	// Try to extract handler name
-	if sel, ok := callExpr.Args[1].(*ast.SelectorExpr); ok {
-		action.Handler = sel.Sel.Name
+	// Classify the handler. Selectors on an identifier that isn't an
+	// imported package are assumed to be component methods (c.handleEdit).
+	switch h := callExpr.Args[1].(type) {
+	case *ast.SelectorExpr:
+		if x, ok := h.X.(*ast.Ident); ok && imports[x.Name] {
+			action.Handler = g.typeToString(h)
+			action.HandlerKind = HandlerFunc
+			action.Signature = HandlerSigRuntime
+		} else if ok {
+			action.Handler = h.Sel.Name
+			action.HandlerKind = HandlerMethod
+		} else {
+			action.Handler = g.typeToString(h)
+			action.HandlerKind = HandlerValue
+			action.Signature = HandlerSigRuntime
+		}
+	case *ast.Ident:
+		action.Handler = h.Name
+		action.HandlerKind = HandlerFunc
+		action.Signature = HandlerSigRuntime
+	case *ast.FuncLit:
+		action.HandlerKind = HandlerLiteral
+		sig := g.funcSignature(h.Type, decls)
+		action.Signature, action.FormType, action.params = sig.signature, sig.formType, sig.params
+	default:
+		action.Handler = g.typeToString(h)
+		action.HandlerKind = HandlerValue
+		action.Signature = HandlerSigRuntime
 	}
 
 	return &action
@@ -596,29 +1067,29 @@ func (g *Generator) typeToString(expr ast.Expr) string {
 	}
 }
 
-// parseHXTag parses an hx struct tag.
-func parseHXTag(tagStr string) (key string, omitEmpty bool, exclude bool) {
-	// Find hx:"..." in the tag string
-	for _, part := range strings.Split(tagStr, " ") {
-		if strings.HasPrefix(part, `hx:"`) {
-			value := strings.TrimPrefix(part, `hx:"`)
-			value = strings.TrimSuffix(value, `"`)
-
-			if value == "-" {
-				return "", false, true
-			}
+// parseHXTag parses an hx struct tag: the props key, then options such as
+// omitempty and the constraints described by PropConstraints.
+func parseHXTag(tagStr string) (key string, omitEmpty bool, exclude bool, constraints PropConstraints, err error) {
+	value, ok := reflect.StructTag(tagStr).Lookup("hx")
+	if !ok {
+		return "", false, false, constraints, nil
+	}
+	if value == "-" {
+		return "", false, true, constraints, nil
+	}
 
-			parts := strings.Split(value, ",")
-			key = parts[0]
-			for _, p := range parts[1:] {
-				if p == "omitempty" {
-					omitEmpty = true
-				}
-			}
-			return key, omitEmpty, false
+	parts := strings.Split(value, ",")
+	key = parts[0]
+	for _, p := range parts[1:] {
+		if p == "omitempty" {
+			omitEmpty = true
+			continue
+		}
+		if err := constraints.parse(p); err != nil {
+			return "", false, false, PropConstraints{}, err
 		}
 	}
-	return "", false, false
+	return key, omitEmpty, false, constraints, nil
 }
 
 // isScalarType checks if a type should be auto-serialized.
@@ -4,6 +4,9 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -52,6 +55,105 @@ func (c *Comp) handler(ctx context.Context, props Props, w http.ResponseWriter)
 `,
 			expected: HandlerSigCtxPropsWriter,
 		},
+		{
+			name: "ctx, props, and form struct",
+			code: `
+package test
+import "context"
+type Props struct{}
+type Form struct{}
+type Result struct{}
+func (c *Comp) handler(ctx context.Context, props Props, form Form) Result { return Result{} }
+`,
+			expected: HandlerSigCtxPropsForm,
+		},
+		{
+			name: "ctx, props, response writer and request",
+			code: `
+package test
+import (
+	"context"
+	"net/http"
+)
+type Props struct{}
+type Result struct{}
+func (c *Comp) handler(ctx context.Context, props Props, w http.ResponseWriter, r *http.Request) Result { return Result{} }
+`,
+			expected: HandlerSigCtxPropsWriterRequest,
+		},
+		{
+			name: "form type that isn't a struct",
+			code: `
+package test
+import "context"
+type Props struct{}
+type Form map[string]string
+type Result struct{}
+func (c *Comp) handler(ctx context.Context, props Props, form Form) Result { return Result{} }
+`,
+			expected: HandlerSigUnsupported,
+		},
+		{
+			name: "form type not declared in the package",
+			code: `
+package test
+import (
+	"context"
+	"net/url"
+)
+type Props struct{}
+type Result struct{}
+func (c *Comp) handler(ctx context.Context, props Props, form url.Values) Result { return Result{} }
+`,
+			expected: HandlerSigUnsupported,
+		},
+		{
+			name: "builtin third parameter",
+			code: `
+package test
+import "context"
+type Props struct{}
+type Result struct{}
+func (c *Comp) handler(ctx context.Context, props Props, id string) Result { return Result{} }
+`,
+			expected: HandlerSigUnsupported,
+		},
+		{
+			name: "pointer to form struct",
+			code: `
+package test
+import "context"
+type Props struct{}
+type Form struct{}
+type Result struct{}
+func (c *Comp) handler(ctx context.Context, props Props, form *Form) Result { return Result{} }
+`,
+			expected: HandlerSigUnsupported,
+		},
+		{
+			name: "request and writer swapped",
+			code: `
+package test
+import (
+	"context"
+	"net/http"
+)
+type Props struct{}
+type Result struct{}
+func (c *Comp) handler(ctx context.Context, props Props, r *http.Request, w http.ResponseWriter) Result { return Result{} }
+`,
+			expected: HandlerSigUnsupported,
+		},
+		{
+			name: "props only",
+			code: `
+package test
+type Props struct{}
+type Result struct{}
+func (c *Comp) handler(props Props) Result { return Result{} }
+`,
+			expected: HandlerSigUnsupported,
+		},
 	}
 
 	g := New(Options{})
@@ -63,6 +165,7 @@ func (c *Comp) handler(ctx context.Context, props Props, w http.ResponseWriter)
 			if err != nil {
 				t.Fatalf("Failed to parse code: %v", err)
 			}
+			decls := packageTypeDecls(&ast.Package{Name: "test", Files: map[string]*ast.File{"test.go": file}})
 
 			// Find the function declaration
 			for _, decl := range file.Decls {
@@ -74,7 +177,7 @@ func (c *Comp) handler(ctx context.Context, props Props, w http.ResponseWriter)
 					continue
 				}
 
-				sig := g.detectHandlerSignature(funcDecl.Type.Params.List)
+				sig := g.detectHandlerSignature(funcDecl.Type.Params.List, decls)
 				if sig != tt.expected {
 					t.Errorf("detectHandlerSignature() = %v, want %v", sig, tt.expected)
 				}
@@ -85,60 +188,240 @@ func (c *Comp) handler(ctx context.Context, props Props, w http.ResponseWriter)
 	}
 }
 
-func TestFindHandlerSignatures(t *testing.T) {
+func TestGenerateUnsupportedHandlerSignature(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  string
+		wantErr string
+	}{
+		{"builtin", "ctx context.Context, props ItemProps, id string",
+			"unsupported handler signature func(context.Context, ItemProps, string) for handleSave"},
+		{"pointer form", "ctx context.Context, props ItemProps, form *Item",
+			"unsupported handler signature func(context.Context, ItemProps, *Item) for handleSave"},
+		{"imported type", "ctx context.Context, props ItemProps, form http.Header",
+			"unsupported handler signature func(context.Context, ItemProps, http.Header) for handleSave"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeManifestPackage(t)
+			src := strings.Replace(manifestSource,
+				"handleSave(ctx context.Context, props ItemProps)",
+				"handleSave("+tt.params+")", 1)
+			if err := os.WriteFile(filepath.Join(dir, "item.go"), []byte(src), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			err := New(Options{}).Generate(dir)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Generate() error = %v, want %q", err, tt.wantErr)
+			}
+			if _, err := os.Stat(filepath.Join(dir, "item_hx.go")); !os.IsNotExist(err) {
+				t.Errorf("item_hx.go written for unsupported handler: %v", err)
+			}
+		})
+	}
+}
+
+func TestFindActionsMethodSignatures(t *testing.T) {
 	code := `
 package test
 
 import (
 	"context"
 	"net/http"
+
+	"github.com/pthm/hxcmp"
 )
 
 type Props struct{}
-type Result struct{}
 
-type Comp struct{}
+type EditForm struct {
+	Title string
+}
 
-func (c *Comp) simpleHandler(ctx context.Context, props Props) Result {
-	return Result{}
+type Comp struct {
+	*hxcmp.Component[Props]
 }
 
-func (c *Comp) requestHandler(ctx context.Context, props Props, r *http.Request) Result {
-	return Result{}
+func New() *Comp {
+	c := &Comp{Component: hxcmp.New[Props]("comp")}
+	c.Action("simple", c.simpleHandler)
+	c.Action("request", c.requestHandler)
+	c.Action("writer", c.writerHandler)
+	c.Action("both", c.bothHandler)
+	c.Action("form", c.formHandler)
+	return c
 }
 
-func (c *Comp) writerHandler(ctx context.Context, props Props, w http.ResponseWriter) Result {
-	return Result{}
+func (c *Comp) simpleHandler(ctx context.Context, props Props) hxcmp.Result[Props] {
+	return hxcmp.OK(props)
+}
+
+func (c *Comp) requestHandler(ctx context.Context, props Props, r *http.Request) hxcmp.Result[Props] {
+	return hxcmp.OK(props)
+}
+
+func (c *Comp) writerHandler(ctx context.Context, props Props, w http.ResponseWriter) hxcmp.Result[Props] {
+	return hxcmp.OK(props)
+}
+
+func (c *Comp) bothHandler(ctx context.Context, props Props, w http.ResponseWriter, r *http.Request) hxcmp.Result[Props] {
+	return hxcmp.OK(props)
+}
+
+func (c *Comp) formHandler(ctx context.Context, props Props, form EditForm) hxcmp.Result[Props] {
+	return hxcmp.OK(props)
 }
 `
 
 	g := New(Options{})
-	fset := token.NewFileSet()
-	file, err := parser.ParseFile(fset, "test.go", code, 0)
+	file, err := parser.ParseFile(g.fset, "test.go", code, 0)
 	if err != nil {
 		t.Fatalf("Failed to parse code: %v", err)
 	}
+	pkg := &ast.Package{Name: "test", Files: map[string]*ast.File{"test.go": file}}
 
-	sigs := g.findHandlerSignatures(file, "Comp")
+	decls := packageTypeDecls(pkg)
+	actions := g.findActions(file, g.findHandlers(file, "Comp", decls), decls)
+	g.resolveFuncHandlers(pkg, actions, decls)
 
-	tests := []struct {
-		name     string
-		expected HandlerSignature
+	want := map[string]struct {
+		sig  HandlerSignature
+		form string
 	}{
-		{"simpleHandler", HandlerSigCtxProps},
-		{"requestHandler", HandlerSigCtxPropsRequest},
-		{"writerHandler", HandlerSigCtxPropsWriter},
+		"both":    {HandlerSigCtxPropsWriterRequest, ""},
+		"form":    {HandlerSigCtxPropsForm, "EditForm"},
+		"request": {HandlerSigCtxPropsRequest, ""},
+		"simple":  {HandlerSigCtxProps, ""},
+		"writer":  {HandlerSigCtxPropsWriter, ""},
+	}
+	if len(actions) != len(want) {
+		t.Fatalf("found %d actions, want %d", len(actions), len(want))
+	}
+	for _, a := range actions {
+		w := want[a.Name]
+		if a.HandlerKind != HandlerMethod || a.Signature != w.sig || a.FormType != w.form {
+			t.Errorf("%s: kind = %s, signature = %v, form = %q; want method, %v, %q",
+				a.Name, a.HandlerKind, a.Signature, a.FormType, w.sig, w.form)
+		}
 	}
+}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			sig, ok := sigs[tt.name]
-			if !ok {
-				t.Fatalf("Handler %s not found", tt.name)
-			}
-			if sig != tt.expected {
-				t.Errorf("Signature for %s = %v, want %v", tt.name, sig, tt.expected)
-			}
-		})
+func TestFindActionsHandlerKinds(t *testing.T) {
+	code := `
+package test
+
+import (
+	"context"
+	"net/http"
+
+	"example.com/handlers"
+	"github.com/pthm/hxcmp"
+)
+
+type Props struct{}
+
+type Comp struct {
+	*hxcmp.Component[Props]
+	store *Store
+}
+
+func New() *Comp {
+	c := &Comp{Component: hxcmp.New[Props]("comp")}
+	c.Action("method", c.handleMethod)
+	c.Action("literal", func(ctx context.Context, props Props, w http.ResponseWriter, r *http.Request) hxcmp.Result[Props] {
+		return hxcmp.OK(props)
+	})
+	c.Action("local", handleLocal)
+	c.Action("imported", handlers.Save)
+	c.Action("value", c.store.Save)
+	return c
+}
+
+func (c *Comp) handleMethod(ctx context.Context, props Props) hxcmp.Result[Props] {
+	return hxcmp.OK(props)
+}
+
+func handleLocal(ctx context.Context, props Props, r *http.Request) hxcmp.Result[Props] {
+	return hxcmp.OK(props)
+}
+`
+
+	g := New(Options{})
+	file, err := parser.ParseFile(g.fset, "test.go", code, 0)
+	if err != nil {
+		t.Fatalf("Failed to parse code: %v", err)
+	}
+	pkg := &ast.Package{Name: "test", Files: map[string]*ast.File{"test.go": file}}
+
+	decls := packageTypeDecls(pkg)
+	actions := g.findActions(file, g.findHandlers(file, "Comp", decls), decls)
+	g.resolveFuncHandlers(pkg, actions, decls)
+
+	want := map[string]struct {
+		handler string
+		kind    HandlerKind
+		sig     HandlerSignature
+	}{
+		"imported": {"handlers.Save", HandlerFunc, HandlerSigRuntime},
+		"literal":  {"", HandlerLiteral, HandlerSigCtxPropsWriterRequest},
+		"local":    {"handleLocal", HandlerFunc, HandlerSigCtxPropsRequest},
+		"method":   {"handleMethod", HandlerMethod, HandlerSigCtxProps},
+		"value":    {"c.store.Save", HandlerValue, HandlerSigRuntime},
+	}
+	if len(actions) != len(want) {
+		t.Fatalf("found %d actions, want %d", len(actions), len(want))
+	}
+	for _, a := range actions {
+		w := want[a.Name]
+		if a.Handler != w.handler || a.HandlerKind != w.kind || a.Signature != w.sig {
+			t.Errorf("%s: handler = %q, kind = %s, signature = %v; want %q, %s, %v",
+				a.Name, a.Handler, a.HandlerKind, a.Signature, w.handler, w.kind, w.sig)
+		}
+	}
+}
+
+func TestFindActionsBuilderChains(t *testing.T) {
+	code := `
+package test
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/pthm/hxcmp"
+)
+
+type Props struct{}
+
+type Comp struct {
+	*hxcmp.Component[Props]
+}
+
+func New() *Comp {
+	c := &Comp{Component: hxcmp.New[Props]("comp")}
+	c.Action("before", c.handle).Method(http.MethodDelete).Use(audit)
+	c.Action("after", c.handle).Use(audit).Method(http.MethodGet)
+	return c
+}
+
+func (c *Comp) handle(ctx context.Context, props Props) hxcmp.Result[Props] {
+	return hxcmp.OK(props)
+}
+`
+
+	g := New(Options{})
+	file, err := parser.ParseFile(g.fset, "test.go", code, 0)
+	if err != nil {
+		t.Fatalf("Failed to parse code: %v", err)
+	}
+
+	methods := map[string]string{}
+	for _, a := range g.findActions(file, g.findHandlers(file, "Comp", nil), nil) {
+		methods[a.Name] = a.Method
+	}
+	if methods["before"] != "DELETE" || methods["after"] != "GET" || len(methods) != 2 {
+		t.Errorf("methods = %v, want before: DELETE, after: GET", methods)
 	}
 }
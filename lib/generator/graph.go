@@ -0,0 +1,936 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Graph describes how components are connected: the events their actions
+// emit, the components that listen to them, and the components that render
+// or wire up other components through their Cmp() getters.
+//
+// It is built from a static pass over Go sources and .templ files, so only
+// literal event names and direct getter calls are found.
+type Graph struct {
+	Components []GraphComponent `json:"components"`
+	Files      []GraphFile      `json:"files"`
+	Events     []GraphEvent     `json:"events"`
+	Refs       []GraphRef       `json:"refs"`
+}
+
+// GraphComponent is a component node.
+//
+// Nodes are identified by import path, or by directory for packages outside
+// a module, since packages in different directories may share a name.
+type GraphComponent struct {
+	ID       string   `json:"id"`    // Import path and type, e.g. "example.com/app/components.TodoList"
+	Label    string   `json:"label"` // Package name and type, e.g. "components.TodoList"
+	Dir      string   `json:"dir"`
+	TypeName string   `json:"type"`
+	Actions  []string `json:"actions"`
+}
+
+// GraphFile is a source outside any component that emits, listens to or
+// references something.
+type GraphFile struct {
+	ID    string `json:"id"`    // Import path and file, e.g. "example.com/app/layout.templ"
+	Label string `json:"label"` // Package name and file, e.g. "main/layout.templ"
+}
+
+// GraphEvent is an event and the code that emits and listens to it.
+type GraphEvent struct {
+	Name      string       `json:"name"`
+	Emitters  []GraphPoint `json:"emitters"`
+	Listeners []GraphPoint `json:"listeners"`
+}
+
+// GraphPoint identifies where an event is emitted or listened to: a
+// component, optionally one of its actions, or a file outside any component.
+type GraphPoint struct {
+	Node   string `json:"node"` // GraphComponent.ID or GraphFile.ID
+	Action string `json:"action,omitempty"`
+}
+
+// GraphRef is a reference from one node to another component through its
+// Cmp() getter.
+type GraphRef struct {
+	From   string `json:"from"`
+	To     string `json:"to"`               // GraphComponent.ID
+	Action string `json:"action,omitempty"` // Wired action; empty for renders
+	Kind   string `json:"kind"`             // "renders" or "wires"
+}
+
+// Label returns the label of the component or file with ID node, or node
+// itself if there is none.
+func (gr *Graph) Label(node string) string {
+	for _, c := range gr.Components {
+		if c.ID == node {
+			return c.Label
+		}
+	}
+	for _, f := range gr.Files {
+		if f.ID == node {
+			return f.Label
+		}
+	}
+	return node
+}
+
+// OrphanEvents returns the events that are emitted but never listened to.
+func (gr *Graph) OrphanEvents() []GraphEvent {
+	var events []GraphEvent
+	for _, ev := range gr.Events {
+		if len(ev.Listeners) == 0 {
+			events = append(events, ev)
+		}
+	}
+	return events
+}
+
+// OrphanListeners returns the events that are listened to but never emitted.
+func (gr *Graph) OrphanListeners() []GraphEvent {
+	var events []GraphEvent
+	for _, ev := range gr.Events {
+		if len(ev.Emitters) == 0 {
+			events = append(events, ev)
+		}
+	}
+	return events
+}
+
+// Graph builds the component and event graph for the given package
+// patterns.
+//
+// Events are found in Emits and Listens declarations, Trigger calls with a
+// literal or Event<Name> constant, generated Emit<Name> and Listen<Name>
+// calls, and hx-trigger attributes listening on "from:" another element,
+// which is how HX-Trigger events are received. Trigger calls in action
+// handlers are attributed to the action.
+func (g *Generator) Graph(patterns ...string) (*Graph, error) {
+	packages, err := g.findPackages(patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	b := newGraphBuilder()
+	for _, pkgPath := range packages {
+		pkgs, err := g.parsePackage(pkgPath)
+		if err != nil {
+			return nil, fmt.Errorf("package %s: %w", pkgPath, err)
+		}
+		pkgNames := make([]string, 0, len(pkgs))
+		for pkgName := range pkgs {
+			pkgNames = append(pkgNames, pkgName)
+		}
+		sort.Strings(pkgNames)
+
+		key := packageKey(pkgPath)
+		for _, pkgName := range pkgNames {
+			pkg := &graphPackage{key: key, name: pkgName, dir: pkgPath}
+			b.packages[key] = pkg
+			components, err := g.findComponents(pkgs[pkgName])
+			if err != nil {
+				return nil, fmt.Errorf("package %s: %w", pkgPath, err)
+			}
+			b.addComponents(pkg, components)
+			b.scanGo(pkg, pkgs[pkgName])
+			if err := b.scanTempl(pkg); err != nil {
+				return nil, fmt.Errorf("package %s: %w", pkgPath, err)
+			}
+		}
+	}
+
+	return b.build(), nil
+}
+
+// graphBuilder accumulates the nodes and edges of a Graph.
+type graphBuilder struct {
+	packages   map[string]*graphPackage // By key
+	components map[string]*GraphComponent
+	byPackage  map[string][]*ComponentInfo // Components by package key
+	handlers   map[string]GraphPoint       // "key.Type.method" or "key.func" to action
+	idents     map[string]string           // "key.Ident" to event name, from declarations
+	files      map[string]bool
+	fileLabels map[string]string // File node to label
+	emits      map[string]map[GraphPoint]bool
+	listens    map[string]map[GraphPoint]bool
+	refs       map[GraphRef]bool
+	pending    []identUse // Event<Name>, Emit<Name> and Listen<Name> uses
+
+	// Getter calls, resolved once every package's components are known
+	pendingRefs []refUse
+}
+
+// graphPackage is a package of the graph. Its key, the import path or, for
+// packages outside a module, the directory, prefixes the IDs of its nodes.
+type graphPackage struct {
+	key  string
+	name string
+	dir  string
+}
+
+// graphScope is where an identifier is used: the package, and the imports of
+// the file, by local name, that qualified identifiers resolve through.
+type graphScope struct {
+	pkg     *graphPackage
+	imports map[string]string
+}
+
+// refUse is a method call on a component getter.
+type refUse struct {
+	scope    graphScope
+	at       GraphPoint
+	qual     string // Package qualifier of the getter; empty for the scope's own
+	typeName string // Component type the getter would return
+	method   string
+}
+
+// identUse is a use of a generated event identifier, resolved once every
+// package's declarations are known.
+type identUse struct {
+	scope  graphScope
+	qual   string // Package qualifier; empty for the scope's own
+	ident  string
+	at     GraphPoint
+	listen bool
+}
+
+func newGraphBuilder() *graphBuilder {
+	return &graphBuilder{
+		packages:   make(map[string]*graphPackage),
+		components: make(map[string]*GraphComponent),
+		byPackage:  make(map[string][]*ComponentInfo),
+		handlers:   make(map[string]GraphPoint),
+		idents:     make(map[string]string),
+		files:      make(map[string]bool),
+		fileLabels: make(map[string]string),
+		emits:      make(map[string]map[GraphPoint]bool),
+		listens:    make(map[string]map[GraphPoint]bool),
+		refs:       make(map[GraphRef]bool),
+	}
+}
+
+// packageKey returns the key of the package in dir: its import path, or
+// its directory if it isn't in a module.
+func packageKey(dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return filepath.ToSlash(dir)
+	}
+	if root := moduleRoot(abs); root != "" {
+		if mod := modulePath(root); mod != "" {
+			rel, err := filepath.Rel(root, abs)
+			if err == nil && rel == "." {
+				return mod
+			}
+			if err == nil {
+				return mod + "/" + filepath.ToSlash(rel)
+			}
+		}
+	}
+	return filepath.ToSlash(abs)
+}
+
+// modulePath returns the module path declared in root/go.mod, or "".
+func modulePath(root string) string {
+	data, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(line), "module"); ok && rest != "" && (rest[0] == ' ' || rest[0] == '\t') {
+			return strings.Trim(strings.TrimSpace(rest), `"`)
+		}
+	}
+	return ""
+}
+
+// importPaths returns the imports of a Go file by local name.
+func importPaths(file *ast.File) map[string]string {
+	imports := make(map[string]string)
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := path.Base(importPath)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		imports[name] = importPath
+	}
+	return imports
+}
+
+// resolve returns the key of the package qual refers to in scope, or "" if
+// it isn't part of the graph.
+func (b *graphBuilder) resolve(scope graphScope, qual string) string {
+	if qual == "" {
+		return scope.pkg.key
+	}
+	if importPath, ok := scope.imports[qual]; ok && b.packages[importPath] != nil {
+		return importPath
+	}
+	// The local name may be the package name rather than the last path element
+	imported := false
+	for _, importPath := range scope.imports {
+		if pkg := b.packages[importPath]; pkg != nil && pkg.name == qual {
+			return importPath
+		}
+		imported = imported || path.Base(importPath) == qual
+	}
+	if imported {
+		return ""
+	}
+
+	// Import paths outside a module can't be matched to directories; fall
+	// back to the package name when it is unambiguous
+	found := ""
+	for key, pkg := range b.packages {
+		if pkg.name == qual {
+			if found != "" {
+				return ""
+			}
+			found = key
+		}
+	}
+	return found
+}
+
+// addComponents adds a package's components with their actions and declared
+// events.
+func (b *graphBuilder) addComponents(pkg *graphPackage, components []*ComponentInfo) {
+	b.byPackage[pkg.key] = append(b.byPackage[pkg.key], components...)
+	for _, comp := range components {
+		id := pkg.key + "." + comp.TypeName
+		gc := &GraphComponent{
+			ID:       id,
+			Label:    pkg.name + "." + comp.TypeName,
+			Dir:      pkg.dir,
+			TypeName: comp.TypeName,
+			Actions:  []string{},
+		}
+		for _, a := range comp.Actions {
+			gc.Actions = append(gc.Actions, a.Name)
+			switch a.HandlerKind {
+			case HandlerMethod:
+				b.handlers[id+"."+a.Handler] = GraphPoint{Node: id, Action: a.Name}
+			case HandlerFunc:
+				if !strings.Contains(a.Handler, ".") {
+					b.handlers[pkg.key+"."+a.Handler] = GraphPoint{Node: id, Action: a.Name}
+				}
+			}
+		}
+		b.components[id] = gc
+
+		for _, ev := range comp.Emits {
+			b.idents[pkg.key+"."+ev.Ident] = ev.Name
+			b.emit(ev.Name, GraphPoint{Node: id})
+		}
+		for _, ev := range comp.Listens {
+			b.idents[pkg.key+"."+ev.Ident] = ev.Name
+			b.listen(ev.Name, GraphPoint{Node: id})
+		}
+	}
+}
+
+func (b *graphBuilder) emit(event string, at GraphPoint) {
+	b.use(at.Node)
+	if b.emits[event] == nil {
+		b.emits[event] = make(map[GraphPoint]bool)
+	}
+	b.emits[event][at] = true
+}
+
+func (b *graphBuilder) listen(event string, at GraphPoint) {
+	b.use(at.Node)
+	if b.listens[event] == nil {
+		b.listens[event] = make(map[GraphPoint]bool)
+	}
+	b.listens[event][at] = true
+}
+
+// use records that a node has an edge, adding it to Graph.Files if it isn't
+// a component.
+func (b *graphBuilder) use(node string) {
+	if _, ok := b.components[node]; !ok {
+		b.files[node] = true
+	}
+}
+
+// scanGo finds event uses and getter references in a package's Go files,
+// including the Go code templ generates.
+func (b *graphBuilder) scanGo(pkg *graphPackage, astPkg *ast.Package) {
+	filenames := make([]string, 0, len(astPkg.Files))
+	for filename := range astPkg.Files {
+		if !isGeneratedFile(filepath.Base(filename)) {
+			filenames = append(filenames, filename)
+		}
+	}
+	sort.Strings(filenames)
+
+	for _, filename := range filenames {
+		file := astPkg.Files[filename]
+		scope := graphScope{pkg: pkg, imports: importPaths(file)}
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil {
+				continue
+			}
+			at := b.funcPoint(pkg, filename, fn)
+			b.scanNode(scope, fn.Body, at)
+		}
+	}
+}
+
+// funcPoint attributes a function to a component: its receiver, else its
+// first parameter of a component type, else the component declared in the
+// file it was generated from.
+func (b *graphBuilder) funcPoint(pkg *graphPackage, filename string, fn *ast.FuncDecl) GraphPoint {
+	var node string
+	if fn.Recv != nil && len(fn.Recv.List) > 0 {
+		node = b.componentID(pkg.key, typeName(fn.Recv.List[0].Type))
+	}
+	if node == "" && fn.Type.Params != nil {
+		for _, p := range fn.Type.Params.List {
+			if node = b.componentID(pkg.key, typeName(p.Type)); node != "" {
+				break
+			}
+		}
+	}
+	if node == "" {
+		node = b.fileOwner(pkg, filename)
+	}
+
+	key := pkg.key + "." + fn.Name.Name
+	if fn.Recv != nil {
+		key = node + "." + fn.Name.Name
+	}
+	if handler, ok := b.handlers[key]; ok {
+		return handler
+	}
+	return GraphPoint{Node: node}
+}
+
+// typeName returns the name of a T or *T type expression.
+func typeName(expr ast.Expr) string {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// componentID returns the ID of the component named typeName in the package
+// with key, or "".
+func (b *graphBuilder) componentID(key, typeName string) string {
+	if _, ok := b.components[key+"."+typeName]; ok && typeName != "" {
+		return key + "." + typeName
+	}
+	return ""
+}
+
+// fileOwner returns the component declared in the Go file a source belongs
+// to (todolist.go for todolist.templ and todolist_templ.go), or the source
+// itself as a file node.
+func (b *graphBuilder) fileOwner(pkg *graphPackage, filename string) string {
+	base := filepath.Base(filename)
+	stem := strings.TrimSuffix(strings.TrimSuffix(strings.TrimSuffix(base, ".go"), ".templ"), "_templ")
+	dir := filepath.Dir(filename)
+	for _, comp := range b.byPackage[pkg.key] {
+		if filepath.Dir(comp.SourceFile) == dir && filepath.Base(comp.SourceFile) == stem+".go" {
+			return pkg.key + "." + comp.TypeName
+		}
+	}
+	if strings.HasSuffix(base, "_templ.go") {
+		base = stem + ".templ"
+	}
+	node := pkg.key + "/" + base
+	b.fileLabels[node] = pkg.name + "/" + base
+	return node
+}
+
+// scanNode records the event uses and references in a function body. Trigger
+// calls inside function literals registered with c.Action are attributed to
+// that action.
+func (b *graphBuilder) scanNode(scope graphScope, node ast.Node, at GraphPoint) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.BasicLit:
+			if n.Kind == token.STRING {
+				b.scanTriggers(n.Value, at)
+			}
+		case *ast.CallExpr:
+			if name, lit := literalAction(n); lit != nil {
+				if _, ok := b.components[at.Node]; ok {
+					b.scanNode(scope, lit.Body, GraphPoint{Node: at.Node, Action: name})
+					return false
+				}
+			}
+			b.scanCall(scope, n, at)
+		}
+		return true
+	})
+}
+
+// literalAction returns the action name and handler of a
+// c.Action("name", func(...) {...}) call.
+func literalAction(call *ast.CallExpr) (string, *ast.FuncLit) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Action" || len(call.Args) != 2 {
+		return "", nil
+	}
+	name, ok := stringLiteral(call.Args[0])
+	lit, isLit := call.Args[1].(*ast.FuncLit)
+	if !ok || !isLit {
+		return "", nil
+	}
+	return name, lit
+}
+
+// scanCall records a Trigger, Emit<Name>, Listen<Name> or getter call.
+func (b *graphBuilder) scanCall(scope graphScope, call *ast.CallExpr, at GraphPoint) {
+	qual, name := "", ""
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		name = fun.Name
+	case *ast.SelectorExpr:
+		name = fun.Sel.Name
+		if x, ok := fun.X.(*ast.Ident); ok {
+			qual = x.Name
+		}
+		if getter, ok := fun.X.(*ast.CallExpr); ok && b.scanGetterCall(scope, getter, name, at) {
+			return
+		}
+	}
+
+	switch {
+	case name == "Trigger" && len(call.Args) > 0:
+		if event, ok := stringLiteral(call.Args[0]); ok {
+			b.emit(event, at)
+		} else if q, ident, ok := eventConst(call.Args[0]); ok {
+			b.pending = append(b.pending, identUse{scope: scope, qual: q, ident: ident, at: at})
+		}
+	case name == "Listen" && qual == "hxcmp":
+		for _, arg := range call.Args {
+			if event, ok := stringLiteral(arg); ok {
+				b.listen(event, at)
+			} else if q, ident, ok := eventConst(arg); ok {
+				b.pending = append(b.pending, identUse{scope: scope, qual: q, ident: ident, at: at, listen: true})
+			}
+		}
+	case isGeneratedEventFunc(name, "Emit"):
+		b.pending = append(b.pending, identUse{scope: scope, qual: qual, ident: strings.TrimPrefix(name, "Emit"), at: at})
+	case isGeneratedEventFunc(name, "Listen"):
+		b.pending = append(b.pending, identUse{scope: scope, qual: qual, ident: strings.TrimPrefix(name, "Listen"), at: at, listen: true})
+	}
+}
+
+// eventConst returns the package qualifier, empty for the current package,
+// and identifier of an Event<Name> constant expression.
+func eventConst(expr ast.Expr) (qual, ident string, ok bool) {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if isGeneratedEventFunc(e.Name, "Event") {
+			return "", strings.TrimPrefix(e.Name, "Event"), true
+		}
+	case *ast.SelectorExpr:
+		if x, ok := e.X.(*ast.Ident); ok && isGeneratedEventFunc(e.Sel.Name, "Event") {
+			return x.Name, strings.TrimPrefix(e.Sel.Name, "Event"), true
+		}
+	}
+	return "", "", false
+}
+
+// isGeneratedEventFunc reports whether name is prefix followed by an
+// exported identifier, as in EmitTodoChanged.
+func isGeneratedEventFunc(name, prefix string) bool {
+	rest, ok := strings.CutPrefix(name, prefix)
+	return ok && rest != "" && unicode.IsUpper([]rune(rest)[0])
+}
+
+// scanGetterCall records a method call on a <Type>Cmp() getter, such as
+// TodoItemCmp().WireToggle(props) or components.StatsCmp().Lazy(...), and
+// reports whether getter was one.
+func (b *graphBuilder) scanGetterCall(scope graphScope, getter *ast.CallExpr, method string, at GraphPoint) bool {
+	qual, name := "", ""
+	switch fun := getter.Fun.(type) {
+	case *ast.Ident:
+		name = fun.Name
+	case *ast.SelectorExpr:
+		if x, ok := fun.X.(*ast.Ident); ok {
+			qual, name = x.Name, fun.Sel.Name
+		}
+	}
+	typeName, ok := strings.CutSuffix(name, "Cmp")
+	if !ok || typeName == "" {
+		return false
+	}
+	b.pendingRefs = append(b.pendingRefs, refUse{scope: scope, at: at, qual: qual, typeName: typeName, method: method})
+	return true
+}
+
+// ref records a reference through method to the component with ID to, if
+// there is one.
+func (b *graphBuilder) ref(at GraphPoint, to, method string) {
+	if _, ok := b.components[to]; !ok {
+		return
+	}
+	b.use(at.Node)
+
+	r := GraphRef{From: at.Node, To: to, Kind: "renders"}
+	for _, prefix := range []string{"Wire", "AbsoluteURL", "URL"} {
+		if action, ok := strings.CutPrefix(method, prefix); ok {
+			r.Kind = "wires"
+			if action != "Render" {
+				r.Action = b.actionName(to, action)
+			}
+			break
+		}
+	}
+	b.refs[r] = true
+}
+
+// actionName maps the title-cased suffix of a generated method back to the
+// component's action name, or "" if the component has no such action.
+func (b *graphBuilder) actionName(id, suffix string) string {
+	for _, a := range b.components[id].Actions {
+		if camelToTitle(a) == suffix {
+			return a
+		}
+	}
+	return ""
+}
+
+// hxTriggerPattern matches hx-trigger attribute values in templates and in
+// the string literals templ generates from them.
+var hxTriggerPattern = regexp.MustCompile(`hx-trigger=\\?"([^"\\]*)\\?"`)
+
+// scanTriggers records the events listened to by hx-trigger attributes in
+// text. Only triggers with a from: modifier are events from other
+// components; the rest are DOM events on the element itself.
+func (b *graphBuilder) scanTriggers(text string, at GraphPoint) {
+	for _, m := range hxTriggerPattern.FindAllStringSubmatch(text, -1) {
+		for _, spec := range strings.Split(m[1], ",") {
+			fields := strings.Fields(spec)
+			if len(fields) < 2 {
+				continue
+			}
+			for _, f := range fields[1:] {
+				if strings.HasPrefix(f, "from:") {
+					event, _, _ := strings.Cut(fields[0], "[")
+					b.listen(event, at)
+					break
+				}
+			}
+		}
+	}
+}
+
+// templDeclPattern matches a templ component's parameters, templGetterPattern
+// a method call on a component getter, and templImportPattern an import
+// spec.
+var (
+	templDeclPattern   = regexp.MustCompile(`(?m)^templ\s+(?:\([^)]*\)\s*)?\w+\(([^)]*)\)`)
+	templGetterPattern = regexp.MustCompile(`(?:(\w+)\.)?(\w+Cmp)\(\)\.(\w+)\(`)
+	templListenPattern = regexp.MustCompile(`(?:(\w+)\.)?Listen([A-Z]\w*)\(\)`)
+	templImportPattern = regexp.MustCompile(`(?m)^(?:import)?\s*(?:(\w+)\s+)?"([^"]+)"\s*$`)
+)
+
+// templImports returns the imports of a .templ file by local name.
+func templImports(src string) map[string]string {
+	imports := make(map[string]string)
+	header := src
+	if i := strings.Index(src, "\ntempl "); i >= 0 {
+		header = src[:i]
+	}
+	for _, m := range templImportPattern.FindAllStringSubmatch(header, -1) {
+		name := m[1]
+		if name == "" {
+			name = path.Base(m[2])
+		}
+		imports[name] = m[2]
+	}
+	return imports
+}
+
+// scanTempl scans the .templ files of a package that templ has not compiled
+// yet; compiled ones are covered by scanning their _templ.go output.
+func (b *graphBuilder) scanTempl(pkg *graphPackage) error {
+	matches, err := filepath.Glob(filepath.Join(pkg.dir, "*.templ"))
+	if err != nil {
+		return err
+	}
+	for _, file := range matches {
+		if _, err := os.Stat(strings.TrimSuffix(file, ".templ") + "_templ.go"); err == nil {
+			continue
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		src := string(data)
+		scope := graphScope{pkg: pkg, imports: templImports(src)}
+
+		at := GraphPoint{}
+		for _, m := range templDeclPattern.FindAllStringSubmatch(src, -1) {
+			for _, param := range strings.Split(m[1], ",") {
+				fields := strings.Fields(param)
+				if len(fields) == 2 {
+					if id := b.componentID(pkg.key, strings.TrimPrefix(fields[1], "*")); id != "" {
+						at.Node = id
+						break
+					}
+				}
+			}
+			if at.Node != "" {
+				break
+			}
+		}
+		if at.Node == "" {
+			at.Node = b.fileOwner(pkg, file)
+		}
+
+		b.scanTriggers(src, at)
+		for _, m := range templGetterPattern.FindAllStringSubmatch(src, -1) {
+			typeName := strings.TrimSuffix(m[2], "Cmp")
+			b.pendingRefs = append(b.pendingRefs, refUse{scope: scope, at: at, qual: m[1], typeName: typeName, method: m[3]})
+		}
+		for _, m := range templListenPattern.FindAllStringSubmatch(src, -1) {
+			b.pending = append(b.pending, identUse{scope: scope, qual: m[1], ident: m[2], at: at, listen: true})
+		}
+	}
+	return nil
+}
+
+// build resolves generated identifiers and returns the sorted graph.
+func (b *graphBuilder) build() *Graph {
+	for _, use := range b.pending {
+		key := b.resolve(use.scope, use.qual)
+		event, ok := b.idents[key+"."+use.ident]
+		if key == "" || !ok {
+			continue
+		}
+		if use.listen {
+			b.listen(event, use.at)
+		} else {
+			b.emit(event, use.at)
+		}
+	}
+	for _, use := range b.pendingRefs {
+		if key := b.resolve(use.scope, use.qual); key != "" {
+			b.ref(use.at, key+"."+use.typeName, use.method)
+		}
+	}
+
+	gr := &Graph{
+		Components: []GraphComponent{},
+		Files:      []GraphFile{},
+		Events:     []GraphEvent{},
+		Refs:       []GraphRef{},
+	}
+	for _, c := range b.components {
+		gr.Components = append(gr.Components, *c)
+	}
+	sort.Slice(gr.Components, func(i, j int) bool {
+		return gr.Components[i].ID < gr.Components[j].ID
+	})
+	for f := range b.files {
+		gr.Files = append(gr.Files, GraphFile{ID: f, Label: b.fileLabels[f]})
+	}
+	sort.Slice(gr.Files, func(i, j int) bool {
+		return gr.Files[i].ID < gr.Files[j].ID
+	})
+
+	names := make(map[string]bool)
+	for name := range b.emits {
+		names[name] = true
+	}
+	for name := range b.listens {
+		names[name] = true
+	}
+	for name := range names {
+		gr.Events = append(gr.Events, GraphEvent{
+			Name:      name,
+			Emitters:  sortedPoints(b.emits[name]),
+			Listeners: sortedPoints(b.listens[name]),
+		})
+	}
+	sort.Slice(gr.Events, func(i, j int) bool {
+		return gr.Events[i].Name < gr.Events[j].Name
+	})
+
+	for r := range b.refs {
+		gr.Refs = append(gr.Refs, r)
+	}
+	sort.Slice(gr.Refs, func(i, j int) bool {
+		a, c := gr.Refs[i], gr.Refs[j]
+		if a.From != c.From {
+			return a.From < c.From
+		}
+		if a.To != c.To {
+			return a.To < c.To
+		}
+		if a.Kind != c.Kind {
+			return a.Kind < c.Kind
+		}
+		return a.Action < c.Action
+	})
+	return gr
+}
+
+// sortedPoints returns the points in a set sorted by node and action. A
+// component-level point is dropped when one of the component's actions is
+// also present, since the action is more specific.
+func sortedPoints(set map[GraphPoint]bool) []GraphPoint {
+	points := []GraphPoint{}
+	for p := range set {
+		if p.Action == "" && hasActionPoint(set, p.Node) {
+			continue
+		}
+		points = append(points, p)
+	}
+	sort.Slice(points, func(i, j int) bool {
+		if points[i].Node != points[j].Node {
+			return points[i].Node < points[j].Node
+		}
+		return points[i].Action < points[j].Action
+	})
+	return points
+}
+
+func hasActionPoint(set map[GraphPoint]bool, node string) bool {
+	for p := range set {
+		if p.Node == node && p.Action != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteJSON writes the graph as indented JSON.
+func (gr *Graph) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(gr)
+}
+
+// WriteDOT writes the graph in Graphviz DOT format. Components are boxes,
+// actions ellipses, events diamonds and other sources notes. Orphan events
+// are drawn in red.
+func (gr *Graph) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph hxcmp {\n\trankdir=LR;\n")
+
+	for _, c := range gr.Components {
+		fmt.Fprintf(&b, "\t%q [shape=box, label=%q];\n", c.ID, c.Label)
+		for _, a := range c.Actions {
+			fmt.Fprintf(&b, "\t%q [shape=ellipse, label=%q];\n", c.ID+"/"+a, a)
+			fmt.Fprintf(&b, "\t%q -> %q [arrowhead=none, style=dotted];\n", c.ID, c.ID+"/"+a)
+		}
+	}
+	for _, f := range gr.Files {
+		fmt.Fprintf(&b, "\t%q [shape=note, label=%q];\n", f.ID, f.Label)
+	}
+	for _, ev := range gr.Events {
+		attrs := ""
+		if len(ev.Emitters) == 0 || len(ev.Listeners) == 0 {
+			attrs = ", color=red, fontcolor=red"
+		}
+		fmt.Fprintf(&b, "\t%q [shape=diamond, label=%q%s];\n", "event:"+ev.Name, ev.Name, attrs)
+		for _, p := range ev.Emitters {
+			fmt.Fprintf(&b, "\t%q -> %q [label=\"emits\"];\n", p.dotID(), "event:"+ev.Name)
+		}
+		for _, p := range ev.Listeners {
+			fmt.Fprintf(&b, "\t%q -> %q [label=\"listens\"];\n", "event:"+ev.Name, p.dotID())
+		}
+	}
+	for _, r := range gr.Refs {
+		to := r.To
+		if r.Action != "" {
+			to += "/" + r.Action
+		}
+		fmt.Fprintf(&b, "\t%q -> %q [label=%q, style=dashed];\n", r.From, to, r.Kind)
+	}
+
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (p GraphPoint) dotID() string {
+	if p.Action != "" {
+		return p.Node + "/" + p.Action
+	}
+	return p.Node
+}
+
+// WriteMermaid writes the graph as a Mermaid flowchart, with the same shapes
+// as WriteDOT.
+func (gr *Graph) WriteMermaid(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+
+	// Mermaid IDs must be plain identifiers
+	ids := make(map[string]string)
+	id := func(key, prefix string) string {
+		if v, ok := ids[key]; ok {
+			return v
+		}
+		v := fmt.Sprintf("%s%d", prefix, len(ids))
+		ids[key] = v
+		return v
+	}
+
+	for _, c := range gr.Components {
+		fmt.Fprintf(&b, "\t%s[%q]\n", id(c.ID, "c"), c.Label)
+		for _, a := range c.Actions {
+			fmt.Fprintf(&b, "\t%s([%q])\n", id(c.ID+"/"+a, "a"), a)
+			fmt.Fprintf(&b, "\t%s -.- %s\n", id(c.ID, "c"), id(c.ID+"/"+a, "a"))
+		}
+	}
+	for _, f := range gr.Files {
+		fmt.Fprintf(&b, "\t%s[/%q/]\n", id(f.ID, "f"), f.Label)
+	}
+	var orphans []string
+	for _, ev := range gr.Events {
+		eid := id("event:"+ev.Name, "e")
+		fmt.Fprintf(&b, "\t%s{%q}\n", eid, ev.Name)
+		if len(ev.Emitters) == 0 || len(ev.Listeners) == 0 {
+			orphans = append(orphans, eid)
+		}
+		for _, p := range ev.Emitters {
+			fmt.Fprintf(&b, "\t%s -- emits --> %s\n", ids[p.dotID()], eid)
+		}
+		for _, p := range ev.Listeners {
+			fmt.Fprintf(&b, "\t%s -- listens --> %s\n", eid, ids[p.dotID()])
+		}
+	}
+	for _, r := range gr.Refs {
+		to := r.To
+		if r.Action != "" {
+			to += "/" + r.Action
+		}
+		fmt.Fprintf(&b, "\t%s -. %s .-> %s\n", ids[r.From], r.Kind, ids[to])
+	}
+	if len(orphans) > 0 {
+		b.WriteString("\tclassDef orphan stroke:#d33,color:#d33\n")
+		fmt.Fprintf(&b, "\tclass %s orphan\n", strings.Join(orphans, ","))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
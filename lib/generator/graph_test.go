@@ -0,0 +1,279 @@
+package generator
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const graphListSource = `package components
+
+import (
+	"context"
+
+	"github.com/a-h/templ"
+	"github.com/pthm/hxcmp"
+)
+
+type ListProps struct {
+	Filter string ` + "`hx:\"filter\"`" + `
+}
+
+type List struct {
+	*hxcmp.Component[ListProps]
+}
+
+func NewList() *List {
+	c := &List{Component: hxcmp.New[ListProps]("list")}
+	c.Action("clear", func(ctx context.Context, props ListProps) hxcmp.Result[ListProps] {
+		return hxcmp.OK(props).Trigger("list:cleared")
+	})
+	c.Listens("item:saved")
+	return c
+}
+
+func (c *List) Hydrate(ctx context.Context, props *ListProps) error { return nil }
+
+func (c *List) Render(ctx context.Context, props ListProps) templ.Component { return nil }
+`
+
+const graphItemSource = `package components
+
+import (
+	"context"
+
+	"github.com/a-h/templ"
+	"github.com/pthm/hxcmp"
+)
+
+type ItemProps struct {
+	ID string ` + "`hx:\"id\"`" + `
+}
+
+type ItemSaved struct {
+	ID string
+}
+
+type Item struct {
+	*hxcmp.Component[ItemProps]
+}
+
+func NewItem() *Item {
+	c := &Item{Component: hxcmp.New[ItemProps]("item")}
+	c.Action("save", c.handleSave)
+	c.Action("archive", c.handleArchive)
+	c.Emits("item:saved", ItemSaved{})
+	return c
+}
+
+func (c *Item) Hydrate(ctx context.Context, props *ItemProps) error { return nil }
+
+func (c *Item) Render(ctx context.Context, props ItemProps) templ.Component { return nil }
+
+func (c *Item) handleSave(ctx context.Context, props ItemProps) hxcmp.Result[ItemProps] {
+	return EmitItemSaved(hxcmp.OK(props), ItemSaved{ID: props.ID})
+}
+
+func (c *Item) handleArchive(ctx context.Context, props ItemProps) hxcmp.Result[ItemProps] {
+	return hxcmp.OK(props).Trigger(EventItemSaved)
+}
+`
+
+// graphListTempl is a .templ file that templ hasn't compiled yet.
+const graphListTempl = `package components
+
+templ listView(c *List, props ListProps) {
+	<ul { c.WireRender(props)... } hx-trigger="load, filter:changed from:body, item:saved from:body">
+		<button { ItemCmp().WireSave(ItemProps{}) }>Save</button>
+	</ul>
+}
+`
+
+// graphPageTempl is the Go output of templ for a page outside any component.
+const graphPageTempl = `package main
+
+import (
+	"context"
+	"io"
+
+	"github.com/a-h/templ"
+	"example.com/app/components"
+)
+
+func page() templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, "<div hx-trigger=\"list:cleared from:body\">")
+		components.ListCmp().RenderHydrated(ctx, components.ListProps{}).Render(ctx, w)
+		return err
+	})
+}
+`
+
+func writeGraphPackages(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	writeFiles(t, filepath.Join(root, "components"), map[string]string{
+		"list.go":    graphListSource,
+		"list.templ": graphListTempl,
+		"item.go":    graphItemSource,
+	})
+	writeFiles(t, root, map[string]string{
+		"go.mod":        "module example.com/app\n",
+		"page_templ.go": graphPageTempl,
+	})
+	return root
+}
+
+func TestGraph(t *testing.T) {
+	root := writeGraphPackages(t)
+
+	gr, err := New(Options{}).Graph(root + "/...")
+	if err != nil {
+		t.Fatalf("Graph() error = %v", err)
+	}
+
+	wantComponents := []GraphComponent{
+		{ID: "example.com/app/components.Item", Label: "components.Item", Dir: filepath.Join(root, "components"), TypeName: "Item", Actions: []string{"archive", "save"}},
+		{ID: "example.com/app/components.List", Label: "components.List", Dir: filepath.Join(root, "components"), TypeName: "List", Actions: []string{"clear"}},
+	}
+	if !reflect.DeepEqual(gr.Components, wantComponents) {
+		t.Errorf("Components = %+v, want %+v", gr.Components, wantComponents)
+	}
+	if want := []GraphFile{{ID: "example.com/app/page.templ", Label: "main/page.templ"}}; !reflect.DeepEqual(gr.Files, want) {
+		t.Errorf("Files = %v, want %v", gr.Files, want)
+	}
+
+	wantEvents := []GraphEvent{
+		{
+			Name:      "filter:changed",
+			Emitters:  []GraphPoint{},
+			Listeners: []GraphPoint{{Node: "example.com/app/components.List"}},
+		},
+		{
+			Name: "item:saved",
+			Emitters: []GraphPoint{
+				{Node: "example.com/app/components.Item", Action: "archive"},
+				{Node: "example.com/app/components.Item", Action: "save"},
+			},
+			Listeners: []GraphPoint{{Node: "example.com/app/components.List"}},
+		},
+		{
+			Name:      "list:cleared",
+			Emitters:  []GraphPoint{{Node: "example.com/app/components.List", Action: "clear"}},
+			Listeners: []GraphPoint{{Node: "example.com/app/page.templ"}},
+		},
+	}
+	if !reflect.DeepEqual(gr.Events, wantEvents) {
+		t.Errorf("Events = %+v, want %+v", gr.Events, wantEvents)
+	}
+
+	wantRefs := []GraphRef{
+		{From: "example.com/app/components.List", To: "example.com/app/components.Item", Action: "save", Kind: "wires"},
+		{From: "example.com/app/page.templ", To: "example.com/app/components.List", Kind: "renders"},
+	}
+	if !reflect.DeepEqual(gr.Refs, wantRefs) {
+		t.Errorf("Refs = %+v, want %+v", gr.Refs, wantRefs)
+	}
+
+	if orphans := gr.OrphanListeners(); len(orphans) != 1 || orphans[0].Name != "filter:changed" {
+		t.Errorf("OrphanListeners() = %+v", orphans)
+	}
+	if orphans := gr.OrphanEvents(); len(orphans) != 0 {
+		t.Errorf("OrphanEvents() = %+v", orphans)
+	}
+}
+
+func TestGraphOutput(t *testing.T) {
+	gr, err := New(Options{}).Graph(writeGraphPackages(t) + "/...")
+	if err != nil {
+		t.Fatalf("Graph() error = %v", err)
+	}
+
+	var dot bytes.Buffer
+	if err := gr.WriteDOT(&dot); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"digraph hxcmp {",
+		`"example.com/app/components.Item" [shape=box, label="components.Item"];`,
+		`"example.com/app/components.Item/save" -> "event:item:saved" [label="emits"];`,
+		`"event:item:saved" -> "example.com/app/components.List" [label="listens"];`,
+		`"event:filter:changed" [shape=diamond, label="filter:changed", color=red, fontcolor=red];`,
+		`"example.com/app/components.List" -> "example.com/app/components.Item/save" [label="wires", style=dashed];`,
+		`"example.com/app/page.templ" [shape=note, label="main/page.templ"];`,
+	} {
+		if !strings.Contains(dot.String(), want) {
+			t.Errorf("DOT output missing %q:\n%s", want, dot.String())
+		}
+	}
+
+	var mermaid bytes.Buffer
+	if err := gr.WriteMermaid(&mermaid); err != nil {
+		t.Fatal(err)
+	}
+	out := mermaid.String()
+	if !strings.HasPrefix(out, "flowchart LR\n") || strings.Contains(out, "--> \n") || strings.Contains(out, " -- emits -->  ") {
+		t.Errorf("Mermaid output:\n%s", out)
+	}
+	for _, want := range []string{`["components.Item"]`, `[/"main/page.templ"/]`, `{"item:saved"}`, "-- emits -->", "-- listens -->", "-. wires .->", "class "} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Mermaid output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+// graphTwoPackagesPage uses components of two packages with the same name.
+const graphTwoPackagesPage = `package main
+
+import (
+	"context"
+
+	admin "example.com/app/admin/components"
+	"example.com/app/site/components"
+)
+
+func page(ctx context.Context) {
+	admin.ListCmp().WireClear(admin.ListProps{})
+	components.ListCmp().RenderHydrated(ctx, components.ListProps{})
+}
+`
+
+func TestGraphSamePackageNames(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"go.mod":  "module example.com/app\n",
+		"page.go": graphTwoPackagesPage,
+	})
+	for _, dir := range []string{"admin/components", "site/components"} {
+		writeFiles(t, filepath.Join(root, dir), map[string]string{"list.go": graphListSource})
+	}
+
+	gr, err := New(Options{}).Graph(root + "/...")
+	if err != nil {
+		t.Fatalf("Graph() error = %v", err)
+	}
+
+	const admin, site = "example.com/app/admin/components.List", "example.com/app/site/components.List"
+	if len(gr.Components) != 2 || gr.Components[0].ID != admin || gr.Components[1].ID != site {
+		t.Fatalf("Components = %+v", gr.Components)
+	}
+	for _, c := range gr.Components {
+		if c.Label != "components.List" || gr.Label(c.ID) != c.Label {
+			t.Errorf("Label of %s = %q", c.ID, c.Label)
+		}
+	}
+
+	wantEmitters := []GraphPoint{{Node: admin, Action: "clear"}, {Node: site, Action: "clear"}}
+	if len(gr.Events) != 2 || gr.Events[1].Name != "list:cleared" || !reflect.DeepEqual(gr.Events[1].Emitters, wantEmitters) {
+		t.Errorf("Events = %+v", gr.Events)
+	}
+	wantRefs := []GraphRef{
+		{From: "example.com/app/page.go", To: admin, Action: "clear", Kind: "wires"},
+		{From: "example.com/app/page.go", To: site, Kind: "renders"},
+	}
+	if !reflect.DeepEqual(gr.Refs, wantRefs) {
+		t.Errorf("Refs = %+v, want %+v", gr.Refs, wantRefs)
+	}
+}
@@ -0,0 +1,227 @@
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// helpersFile is the per-package generated file that records the input hash.
+const helpersFile = "hx_helpers.go"
+
+// generatorVersion is hashed with a package's inputs so that output recorded
+// by an older generator is regenerated. Bump it whenever a change to the
+// generator's Go code (handler classification, form decoding, constraints,
+// ...) changes what it generates for the same inputs; template changes are
+// hashed with the templates themselves.
+const generatorVersion = "3"
+
+// inputsPrefix starts the header line of hx_helpers.go holding the input hash.
+const inputsPrefix = "// Inputs: "
+
+// pkgRun collects the output of generating a single package. Packages are
+// generated concurrently, so logs and results are buffered and reported in
+// package order.
+type pkgRun struct {
+	path   string
+	report PackageReport
+	lines  []string
+	err    error
+	done   chan struct{}
+
+	emits   []string   // Events emitted by the package's components
+	listens []eventUse // Events listened to by the package's components
+}
+
+func newPkgRun(path string) *pkgRun {
+	return &pkgRun{
+		path: path,
+		report: PackageReport{
+			Dir:         path,
+			Components:  []ComponentReport{},
+			Files:       []FileReport{},
+			Diagnostics: []Diagnostic{},
+		},
+		done: make(chan struct{}),
+	}
+}
+
+// logf records a line of progress output.
+func (r *pkgRun) logf(format string, args ...any) {
+	r.lines = append(r.lines, fmt.Sprintf(format, args...))
+}
+
+// file records what happened to a generated file.
+func (r *pkgRun) file(path string, status FileStatus) {
+	r.report.Files = append(r.report.Files, FileReport{Path: path, Status: status})
+}
+
+// diagnose records an error or warning.
+func (r *pkgRun) diagnose(severity Severity, file, message string) {
+	r.report.Diagnostics = append(r.report.Diagnostics, Diagnostic{
+		Package:  r.path,
+		File:     file,
+		Severity: severity,
+		Message:  message,
+	})
+}
+
+// writeFile writes data to path unless the file already holds exactly those
+// bytes, so unchanged outputs keep their modification time and don't
+// trigger rebuilds. In dry-run mode nothing is written, but the file is
+// still reported as it would have been.
+func (g *Generator) writeFile(r *pkgRun, path string, data []byte) error {
+	if existing, err := os.ReadFile(path); err == nil && bytes.Equal(existing, data) {
+		r.logf("unchanged %s", path)
+		r.file(path, FileSkipped)
+		return nil
+	}
+	r.logf("generating %s", path)
+	r.file(path, FileWritten)
+	if g.opts.DryRun {
+		return nil
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// removeStale removes generated files in the package that this run did not
+// produce, such as the output for a component that was deleted or renamed.
+// *_hx_ext.go files are only removed when extensions are configured, so a
+// plain run doesn't delete the output of a custom generator main.
+func (g *Generator) removeStale(r *pkgRun, keepHelpers bool) error {
+	produced := map[string]bool{helpersFile: keepHelpers}
+	for _, f := range r.report.Files {
+		produced[filepath.Base(f.Path)] = true
+	}
+
+	entries, err := os.ReadDir(r.path)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !isGeneratedFile(name) || produced[name] {
+			continue
+		}
+		if strings.HasSuffix(name, "_hx_ext.go") && !g.hasExtensions() {
+			continue
+		}
+		path := filepath.Join(r.path, name)
+		r.logf("removing %s", path)
+		r.file(path, FileRemoved)
+		if g.opts.DryRun {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isGeneratedFile reports whether name is a file written by the generator.
+func isGeneratedFile(name string) bool {
+	return strings.HasSuffix(name, "_hx.go") || strings.HasSuffix(name, "_hx_test.go") ||
+		strings.HasSuffix(name, "_hx_ext.go") || name == helpersFile
+}
+
+// isInputFile reports whether name is a Go source file the generator reads.
+func isInputFile(name string) bool {
+	return strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go") &&
+		!strings.HasSuffix(name, "_hx.go") && !strings.HasSuffix(name, "_hx_ext.go")
+}
+
+// inputsHash hashes everything that determines a package's generated output:
+// the generator version, the built-in templates, extension templates, the
+// package's source files, and the names of the generated files present. Including the generated
+// names means a deleted output forces regeneration.
+//
+// Returns "" when the output cannot be predicted from inputs alone, which is
+// the case when Go hooks are configured.
+func (g *Generator) inputsHash(pkgPath string) (string, error) {
+	if len(g.opts.Hooks) > 0 {
+		return "", nil
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "generator %s\n", generatorVersion)
+	for _, text := range []string{helpersTemplate, hxTemplate, hxTestTemplate} {
+		io.WriteString(h, text)
+	}
+	for _, text := range g.extTemplateText {
+		io.WriteString(h, text)
+	}
+
+	entries, err := os.ReadDir(pkgPath)
+	if err != nil {
+		return "", err
+	}
+	// os.ReadDir returns entries sorted by name
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || name == helpersFile {
+			continue
+		}
+		switch {
+		case isGeneratedFile(name):
+			fmt.Fprintf(h, "generated %s\n", name)
+		case isInputFile(name):
+			data, err := os.ReadFile(filepath.Join(pkgPath, name))
+			if err != nil {
+				return "", err
+			}
+			fmt.Fprintf(h, "input %s %d\n", name, len(data))
+			h.Write(data)
+		}
+	}
+
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// recordedHash returns the input hash recorded in a package's hx_helpers.go
+// by the last run, or "" if there is none.
+func recordedHash(pkgPath string) string {
+	return recordedHeader(pkgPath, inputsPrefix)
+}
+
+// recordedHeader returns the rest of the header line of a package's
+// hx_helpers.go that starts with prefix, or "" if there is none.
+func recordedHeader(pkgPath, prefix string) string {
+	f, err := os.Open(filepath.Join(pkgPath, helpersFile))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	// The header ends at the package clause
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if value, ok := strings.CutPrefix(line, prefix); ok {
+			return value
+		}
+		if strings.HasPrefix(line, "package ") {
+			break
+		}
+	}
+	return ""
+}
+
+// sortComponents orders components by source file and type name so
+// generation order and output are the same on every run.
+func sortComponents(components []*ComponentInfo) {
+	sort.Slice(components, func(i, j int) bool {
+		a, b := components[i], components[j]
+		if a.SourceFile != b.SourceFile {
+			return a.SourceFile < b.SourceFile
+		}
+		return a.TypeName < b.TypeName
+	})
+}
@@ -0,0 +1,150 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ageFiles sets the modification time of every file in dir to an hour ago
+// and returns that time.
+func ageFiles(t *testing.T, dir string) time.Time {
+	t.Helper()
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if err := os.Chtimes(filepath.Join(dir, entry.Name()), old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return old
+}
+
+func modTime(t *testing.T, path string) time.Time {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info.ModTime()
+}
+
+func TestGenerateUnchangedPackage(t *testing.T) {
+	dir := writeManifestPackage(t)
+	if err := New(Options{}).Generate(dir); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if !strings.HasPrefix(recordedHash(dir), "sha256:") {
+		t.Fatalf("hx_helpers.go has no input hash:\n%s", readFile(t, filepath.Join(dir, helpersFile)))
+	}
+
+	old := ageFiles(t, dir)
+
+	// Skipped entirely: nothing is rewritten
+	if err := New(Options{}).Generate(dir); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	for _, name := range []string{helpersFile, "item_hx.go", "item_hx_test.go"} {
+		if !modTime(t, filepath.Join(dir, name)).Equal(old) {
+			t.Errorf("%s rewritten for unchanged package", name)
+		}
+	}
+
+	// Forced: output is regenerated but identical bytes are not written
+	if err := New(Options{Force: true}).Generate(dir); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	for _, name := range []string{helpersFile, "item_hx.go", "item_hx_test.go"} {
+		if !modTime(t, filepath.Join(dir, name)).Equal(old) {
+			t.Errorf("%s rewritten with identical content", name)
+		}
+	}
+}
+
+func TestGenerateChangedPackage(t *testing.T) {
+	dir := writeManifestPackage(t)
+	if err := New(Options{}).Generate(dir); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	hash := recordedHash(dir)
+
+	// Adding an action changes the component file and test client
+	src := strings.Replace(manifestSource,
+		`c.Action("save", c.handleSave)`,
+		`c.Action("save", c.handleSave)
+	c.Action("archive", c.handleSave)`, 1)
+	if err := os.WriteFile(filepath.Join(dir, "item.go"), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := ageFiles(t, dir)
+
+	if err := New(Options{}).Generate(dir); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if recordedHash(dir) == hash {
+		t.Error("input hash not updated after source change")
+	}
+	if !strings.Contains(readFile(t, filepath.Join(dir, "item_hx.go")), "URLArchive") {
+		t.Error("item_hx.go not regenerated")
+	}
+	if modTime(t, filepath.Join(dir, "item_hx.go")).Equal(old) {
+		t.Error("item_hx.go modification time not updated")
+	}
+
+	// A deleted output is regenerated even though sources are unchanged
+	if err := os.Remove(filepath.Join(dir, "item_hx_test.go")); err != nil {
+		t.Fatal(err)
+	}
+	if err := New(Options{}).Generate(dir); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "item_hx_test.go")); err != nil {
+		t.Errorf("deleted output not regenerated: %v", err)
+	}
+}
+
+func TestGenerateParallelErrors(t *testing.T) {
+	root := t.TempDir()
+	broken := strings.Replace(manifestSource,
+		"func (c *ItemView) handleSave(ctx context.Context, props ItemProps)",
+		"func (c *ItemView) handleSave(ctx context.Context, props ItemProps, form MissingForm)", 1)
+
+	for _, pkg := range []string{"a", "b", "c", "d"} {
+		src := manifestSource
+		if pkg == "b" || pkg == "d" {
+			src = broken
+		}
+		dir := filepath.Join(root, pkg)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "item.go"), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := 0; i < 5; i++ {
+		err := New(Options{Workers: 4}).Generate(root + "/...")
+		if err == nil {
+			t.Fatal("Generate() error = nil, want errors for b and d")
+		}
+		msg := err.Error()
+		b := strings.Index(msg, filepath.Join(root, "b"))
+		d := strings.Index(msg, filepath.Join(root, "d"))
+		if b < 0 || d < 0 || b > d {
+			t.Fatalf("errors not reported in package order:\n%s", msg)
+		}
+	}
+
+	// Packages without errors are still generated
+	for _, pkg := range []string{"a", "c"} {
+		if _, err := os.Stat(filepath.Join(root, pkg, "item_hx.go")); err != nil {
+			t.Errorf("package %s not generated: %v", pkg, err)
+		}
+	}
+}
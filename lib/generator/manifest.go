@@ -0,0 +1,248 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Manifest is a machine-readable description of the components found in a
+// set of packages. It is built from the same ComponentInfo used for code
+// generation, so it always matches what 'hxcmp generate' would produce.
+type Manifest struct {
+	Components []ManifestComponent `json:"components"`
+}
+
+// ManifestComponent describes a single component.
+type ManifestComponent struct {
+	Package    string           `json:"package"`
+	Dir        string           `json:"dir"`
+	TypeName   string           `json:"type"`
+	SourceFile string           `json:"source_file"`
+	PropsType  string           `json:"props_type"`
+	Sensitive  bool             `json:"sensitive"` // Props are encrypted, not just signed
+	Props      []ManifestProp   `json:"props"`
+	Actions    []ManifestAction `json:"actions"`
+	Emits      []ManifestEvent  `json:"emits,omitempty"`
+	Listens    []string         `json:"listens,omitempty"`
+}
+
+// ManifestProp describes a props field.
+type ManifestProp struct {
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Tag       string `json:"tag,omitempty"`
+	OmitEmpty bool   `json:"omitempty"`
+	Exclude   bool   `json:"exclude"`
+
+	// Constraints from the hx tag, e.g. "required,min=1"
+	Constraints string `json:"constraints,omitempty"`
+}
+
+// ManifestAction describes a registered action.
+type ManifestAction struct {
+	Name      string `json:"name"`
+	Method    string `json:"method"`
+	Handler   string `json:"handler"`
+	Signature string `json:"signature"`
+	Form      string `json:"form,omitempty"` // Form input struct, if any
+}
+
+// ManifestEvent describes an event a component emits.
+type ManifestEvent struct {
+	Name    string `json:"name"`
+	Payload string `json:"payload,omitempty"` // Payload struct, if any
+}
+
+// String returns the Go signature the handler was detected as.
+func (s HandlerSignature) String() string {
+	switch s {
+	case HandlerSigCtxProps:
+		return "func(ctx, P) Result[P]"
+	case HandlerSigCtxPropsRequest:
+		return "func(ctx, P, *http.Request) Result[P]"
+	case HandlerSigCtxPropsWriter:
+		return "func(ctx, P, http.ResponseWriter) Result[P]"
+	case HandlerSigCtxPropsForm:
+		return "func(ctx, P, F) Result[P]"
+	case HandlerSigCtxPropsWriterRequest:
+		return "func(ctx, P, http.ResponseWriter, *http.Request) Result[P]"
+	case HandlerSigRuntime:
+		return "checked at runtime"
+	case HandlerSigUnsupported:
+		return "unsupported"
+	default:
+		return fmt.Sprintf("HandlerSignature(%d)", int(s))
+	}
+}
+
+// Manifest builds a manifest for the given package patterns.
+//
+// Components are sorted by source file and type name, and actions by name,
+// so the output is stable across runs.
+func (g *Generator) Manifest(patterns ...string) (*Manifest, error) {
+	packages, err := g.findPackages(patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manifest{Components: []ManifestComponent{}}
+	for _, pkgPath := range packages {
+		pkgs, err := g.parsePackage(pkgPath)
+		if err != nil {
+			return nil, fmt.Errorf("package %s: %w", pkgPath, err)
+		}
+		for pkgName, pkg := range pkgs {
+			components, err := g.findComponents(pkg)
+			if err != nil {
+				return nil, fmt.Errorf("package %s: %w", pkgPath, err)
+			}
+			for _, comp := range components {
+				m.Components = append(m.Components, newManifestComponent(pkgName, pkgPath, comp))
+			}
+		}
+	}
+
+	sort.Slice(m.Components, func(i, j int) bool {
+		a, b := m.Components[i], m.Components[j]
+		if a.SourceFile != b.SourceFile {
+			return a.SourceFile < b.SourceFile
+		}
+		return a.TypeName < b.TypeName
+	})
+
+	return m, nil
+}
+
+// newManifestComponent converts a ComponentInfo to its manifest form.
+func newManifestComponent(pkgName, pkgPath string, comp *ComponentInfo) ManifestComponent {
+	mc := ManifestComponent{
+		Package:    pkgName,
+		Dir:        pkgPath,
+		TypeName:   comp.TypeName,
+		SourceFile: comp.SourceFile,
+		PropsType:  comp.PropsType,
+		Sensitive:  comp.Sensitive,
+		Props:      []ManifestProp{},
+		Actions:    []ManifestAction{},
+	}
+
+	for _, p := range comp.Props {
+		mc.Props = append(mc.Props, ManifestProp{
+			Name:        p.Name,
+			Type:        p.Type,
+			Tag:         p.Tag,
+			OmitEmpty:   p.OmitEmpty,
+			Exclude:     p.Exclude,
+			Constraints: p.Constraints.String(),
+		})
+	}
+
+	for _, a := range comp.Actions {
+		method := a.Method
+		if method == "" {
+			method = "POST"
+		}
+		mc.Actions = append(mc.Actions, ManifestAction{
+			Name:      a.Name,
+			Method:    method,
+			Handler:   a.Handler,
+			Signature: a.Signature.String(),
+			Form:      a.FormType,
+		})
+	}
+	sort.Slice(mc.Actions, func(i, j int) bool {
+		return mc.Actions[i].Name < mc.Actions[j].Name
+	})
+
+	for _, ev := range comp.Emits {
+		mc.Emits = append(mc.Emits, ManifestEvent{Name: ev.Name, Payload: ev.Payload})
+	}
+	for _, ev := range comp.Listens {
+		mc.Listens = append(mc.Listens, ev.Name)
+	}
+
+	return mc
+}
+
+// WriteJSON writes the manifest as indented JSON.
+func (m *Manifest) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(m)
+}
+
+// WriteMarkdown writes the manifest as a Markdown document with one section
+// per component, listing its props, actions and events in tables. It holds
+// the same data as WriteJSON.
+func (m *Manifest) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("# Components\n")
+	for _, c := range m.Components {
+		fmt.Fprintf(&b, "\n## %s.%s\n\n", c.Package, c.TypeName)
+		fmt.Fprintf(&b, "- Directory: `%s`\n", c.Dir)
+		fmt.Fprintf(&b, "- Source: `%s`\n", c.SourceFile)
+		fmt.Fprintf(&b, "- Props type: `%s`\n", c.PropsType)
+		fmt.Fprintf(&b, "- Sensitive: %s\n", yesNo(c.Sensitive))
+
+		b.WriteString("\n### Props\n\n")
+		if len(c.Props) == 0 {
+			b.WriteString("None.\n")
+		} else {
+			b.WriteString("| Field | Type | Tag | Omitempty | Excluded | Constraints |\n")
+			b.WriteString("|-------|------|-----|-----------|----------|-------------|\n")
+			for _, p := range c.Props {
+				fmt.Fprintf(&b, "| %s | `%s` | %s | %s | %s | %s |\n",
+					p.Name, p.Type, markdownCode(p.Tag), yesNo(p.OmitEmpty), yesNo(p.Exclude),
+					markdownCode(p.Constraints))
+			}
+		}
+
+		b.WriteString("\n### Actions\n\n")
+		if len(c.Actions) == 0 {
+			b.WriteString("None.\n")
+		} else {
+			b.WriteString("| Action | Method | Handler | Signature | Form |\n")
+			b.WriteString("|--------|--------|---------|-----------|------|\n")
+			for _, a := range c.Actions {
+				fmt.Fprintf(&b, "| %s | %s | %s | `%s` | %s |\n",
+					a.Name, a.Method, markdownCode(a.Handler), a.Signature, markdownCode(a.Form))
+			}
+		}
+
+		b.WriteString("\n### Events\n\n")
+		if len(c.Emits) == 0 && len(c.Listens) == 0 {
+			b.WriteString("None.\n")
+		} else {
+			b.WriteString("| Event | Direction | Payload |\n")
+			b.WriteString("|-------|-----------|---------|\n")
+			for _, ev := range c.Emits {
+				fmt.Fprintf(&b, "| %s | emits | %s |\n", markdownCode(ev.Name), markdownCode(ev.Payload))
+			}
+			for _, name := range c.Listens {
+				fmt.Fprintf(&b, "| %s | listens | - |\n", markdownCode(name))
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownCode wraps s in backticks, or returns an empty cell marker.
+func markdownCode(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return "`" + s + "`"
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,265 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const manifestSource = `package components
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/a-h/templ"
+	"github.com/pthm/hxcmp"
+)
+
+type ItemProps struct {
+	ID    string ` + "`hx:\"id\"`" + `
+	Page  int    ` + "`hx:\"page,omitempty\"`" + `
+	Item  *Item  ` + "`hx:\"-\"`" + `
+}
+
+type Item struct{}
+
+type ItemView struct {
+	*hxcmp.Component[ItemProps]
+}
+
+func NewItemView() *ItemView {
+	c := &ItemView{Component: hxcmp.New[ItemProps]("item")}
+	c.Action("save", c.handleSave)
+	c.Action("delete", c.handleDelete).Method(http.MethodDelete)
+	return c
+}
+
+func (c *ItemView) Hydrate(ctx context.Context, props *ItemProps) error { return nil }
+
+func (c *ItemView) Render(ctx context.Context, props ItemProps) templ.Component { return nil }
+
+func (c *ItemView) handleSave(ctx context.Context, props ItemProps) hxcmp.Result[ItemProps] {
+	return hxcmp.OK(props)
+}
+
+func (c *ItemView) handleDelete(ctx context.Context, props ItemProps, r *http.Request) hxcmp.Result[ItemProps] {
+	return hxcmp.OK(props)
+}
+`
+
+func writeManifestPackage(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "item.go"), []byte(manifestSource), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestManifest(t *testing.T) {
+	dir := writeManifestPackage(t)
+
+	m, err := New(Options{}).Manifest(dir)
+	if err != nil {
+		t.Fatalf("Manifest() error = %v", err)
+	}
+	if len(m.Components) != 1 {
+		t.Fatalf("got %d components, want 1", len(m.Components))
+	}
+
+	c := m.Components[0]
+	if c.Package != "components" || c.TypeName != "ItemView" || c.PropsType != "ItemProps" {
+		t.Errorf("unexpected component: %+v", c)
+	}
+	if c.SourceFile != filepath.Join(dir, "item.go") {
+		t.Errorf("SourceFile = %q", c.SourceFile)
+	}
+
+	expectedProps := []ManifestProp{
+		{Name: "ID", Type: "string", Tag: "id"},
+		{Name: "Page", Type: "int", Tag: "page", OmitEmpty: true},
+		{Name: "Item", Type: "*Item", Exclude: true},
+	}
+	if len(c.Props) != len(expectedProps) {
+		t.Fatalf("got %d props, want %d", len(c.Props), len(expectedProps))
+	}
+	for i, p := range c.Props {
+		if p != expectedProps[i] {
+			t.Errorf("Props[%d] = %+v, want %+v", i, p, expectedProps[i])
+		}
+	}
+
+	expectedActions := []ManifestAction{
+		{Name: "delete", Method: "DELETE", Handler: "handleDelete", Signature: "func(ctx, P, *http.Request) Result[P]"},
+		{Name: "save", Method: "POST", Handler: "handleSave", Signature: "func(ctx, P) Result[P]"},
+	}
+	if len(c.Actions) != len(expectedActions) {
+		t.Fatalf("got %d actions, want %d", len(c.Actions), len(expectedActions))
+	}
+	for i, a := range c.Actions {
+		if a != expectedActions[i] {
+			t.Errorf("Actions[%d] = %+v, want %+v", i, a, expectedActions[i])
+		}
+	}
+}
+
+func TestManifestWriteJSON(t *testing.T) {
+	m, err := New(Options{}).Manifest(writeManifestPackage(t))
+	if err != nil {
+		t.Fatalf("Manifest() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := m.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+
+	var decoded Manifest
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if len(decoded.Components) != 1 || decoded.Components[0].Actions[0].Method != "DELETE" {
+		t.Errorf("unexpected round trip: %+v", decoded)
+	}
+	if !strings.Contains(buf.String(), `"omitempty": true`) {
+		t.Errorf("JSON missing omitempty flag:\n%s", buf.String())
+	}
+}
+
+func TestManifestWriteMarkdown(t *testing.T) {
+	m, err := New(Options{}).Manifest(writeManifestPackage(t))
+	if err != nil {
+		t.Fatalf("Manifest() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := m.WriteMarkdown(&buf); err != nil {
+		t.Fatalf("WriteMarkdown() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"## components.ItemView",
+		"| Page | `int` | `page` | yes | no |",
+		"| Item | `*Item` | - | no | yes |",
+		"| delete | DELETE | `handleDelete` | `func(ctx, P, *http.Request) Result[P]` |",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("markdown missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+const manifestDetailSource = `package components
+
+import (
+	"context"
+
+	"github.com/a-h/templ"
+	"github.com/pthm/hxcmp"
+)
+
+type AccountProps struct {
+	ID     string ` + "`hx:\"id,required\"`" + `
+	Status string ` + "`hx:\"status,default=open,oneof=open|closed\"`" + `
+}
+
+type RenameForm struct {
+	Name string
+}
+
+type AccountSaved struct {
+	ID string
+}
+
+type Account struct {
+	*hxcmp.Component[AccountProps]
+}
+
+func NewAccount() *Account {
+	c := &Account{Component: hxcmp.New[AccountProps]("account").Sensitive()}
+	c.Action("rename", c.handleRename)
+	c.Emits("account:saved", AccountSaved{}).Emits("account:touched")
+	c.Listens("user:changed")
+	return c
+}
+
+func (c *Account) Hydrate(ctx context.Context, props *AccountProps) error { return nil }
+
+func (c *Account) Render(ctx context.Context, props AccountProps) templ.Component { return nil }
+
+func (c *Account) handleRename(ctx context.Context, props AccountProps, form RenameForm) hxcmp.Result[AccountProps] {
+	return hxcmp.OK(props)
+}
+`
+
+func TestManifestDetails(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "account.go"), []byte(manifestDetailSource), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m, err := New(Options{}).Manifest(dir)
+	if err != nil {
+		t.Fatalf("Manifest() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := m.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	var decoded Manifest
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	c := decoded.Components[0]
+	if !c.Sensitive {
+		t.Errorf("Sensitive = false, want true:\n%s", buf.String())
+	}
+	if c.Props[0].Constraints != "required" || c.Props[1].Constraints != "default=open,oneof=open|closed" {
+		t.Errorf("constraints = %q, %q", c.Props[0].Constraints, c.Props[1].Constraints)
+	}
+	if c.Actions[0].Form != "RenameForm" {
+		t.Errorf("Form = %q, want RenameForm", c.Actions[0].Form)
+	}
+	wantEmits := []ManifestEvent{{Name: "account:saved", Payload: "AccountSaved"}, {Name: "account:touched"}}
+	if !reflect.DeepEqual(c.Emits, wantEmits) || !reflect.DeepEqual(c.Listens, []string{"user:changed"}) {
+		t.Errorf("emits = %+v, listens = %v", c.Emits, c.Listens)
+	}
+
+	buf.Reset()
+	if err := m.WriteMarkdown(&buf); err != nil {
+		t.Fatalf("WriteMarkdown() error = %v", err)
+	}
+	for _, want := range []string{
+		"- Directory: `" + dir + "`",
+		"- Sensitive: yes",
+		"| ID | `string` | `id` | no | no | `required` |",
+		"| Status | `string` | `status` | no | no | `default=open,oneof=open|closed` |",
+		"| rename | POST | `handleRename` | `func(ctx, P, F) Result[P]` | `RenameForm` |",
+		"| `account:saved` | emits | `AccountSaved` |",
+		"| `account:touched` | emits | - |",
+		"| `user:changed` | listens | - |",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("markdown missing %q:\n%s", want, buf.String())
+		}
+	}
+
+	// Components that aren't sensitive and have no events say so
+	m, err = New(Options{}).Manifest(writeManifestPackage(t))
+	if err != nil {
+		t.Fatalf("Manifest() error = %v", err)
+	}
+	buf.Reset()
+	if err := m.WriteMarkdown(&buf); err != nil {
+		t.Fatalf("WriteMarkdown() error = %v", err)
+	}
+	if m.Components[0].Sensitive || !strings.Contains(buf.String(), "- Sensitive: no") ||
+		!strings.Contains(buf.String(), "### Events\n\nNone.\n") {
+		t.Errorf("unexpected markdown for plain component:\n%s", buf.String())
+	}
+}
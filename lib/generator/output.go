@@ -11,17 +11,26 @@ import (
 	"unicode"
 )
 
-// generateHelpers generates the hx_helpers.go file for a package.
-func (g *Generator) generateHelpers(pkgPath, pkgName string) error {
-	outputFile := filepath.Join(pkgPath, "hx_helpers.go")
+// generateHelpers generates the hx_helpers.go file for a package, recording
+// the package's input hash and events in its header. It also declares the
+// package's event constants and helpers.
+func (g *Generator) generateHelpers(r *pkgRun, pkgName string, events *packageEvents) error {
+	outputFile := filepath.Join(r.path, helpersFile)
 
-	fmt.Printf("generating %s\n", outputFile)
+	hash, err := g.inputsHash(r.path)
+	if err != nil {
+		return err
+	}
+	if hash == "" {
+		hash = "none"
+	}
 
-	if g.opts.DryRun {
-		return nil
+	decls, err := events.render()
+	if err != nil {
+		return fmt.Errorf("render events: %w", err)
 	}
 
-	code := []byte(fmt.Sprintf(helpersTemplate, pkgName))
+	code := []byte(fmt.Sprintf(helpersTemplate, inputsPrefix+hash+events.header(), pkgName, decls))
 
 	// Format the code
 	formatted, err := format.Source(code)
@@ -29,15 +38,16 @@ func (g *Generator) generateHelpers(pkgPath, pkgName string) error {
 		return fmt.Errorf("format helpers: %w", err)
 	}
 
-	return os.WriteFile(outputFile, formatted, 0644)
+	return g.writeFile(r, outputFile, formatted)
 }
 
 const helpersTemplate = `// Code generated by hxcmp. DO NOT EDIT.
+%s
 
 //go:build !hxcmp_ignore
 
 package %s
-
+%s
 // Helper functions for type conversion.
 // msgpack v5 decodes integers into their smallest matching Go type
 // (e.g. int8 for values 0-127), so all integer types must be handled.
@@ -135,48 +145,146 @@ func toFloat64(v any) float64 {
 }
 `
 
-// generateComponent generates the *_hx.go file for a component.
-func (g *Generator) generateComponent(pkgPath, pkgName string, comp *ComponentInfo) error {
-	// Determine output filename
-	baseName := strings.TrimSuffix(filepath.Base(comp.SourceFile), ".go")
-	outputFile := filepath.Join(pkgPath, baseName+"_hx.go")
+// generateComponent generates the *_hx.go file for a component, and the
+// *_hx_test.go file holding its test client.
+func (g *Generator) generateComponent(r *pkgRun, pkgName string, comp *ComponentInfo) error {
+	pkgPath := r.path
 
-	fmt.Printf("generating %s\n", outputFile)
+	// Determine output filenames
+	baseName := strings.TrimSuffix(filepath.Base(comp.SourceFile), ".go")
 
-	if g.opts.DryRun {
-		return nil
+	outputs := []struct {
+		file string
+		tmpl string
+	}{
+		{filepath.Join(pkgPath, baseName+"_hx.go"), hxTemplate},
+		{filepath.Join(pkgPath, baseName+"_hx_test.go"), hxTestTemplate},
 	}
 
-	// Generate the code
-	code, err := g.renderTemplate(pkgName, comp)
-	if err != nil {
-		return fmt.Errorf("render template: %w", err)
-	}
+	for _, out := range outputs {
+		// Generate the code
+		code, err := g.renderTemplate(out.tmpl, pkgName, comp)
+		if err != nil {
+			return fmt.Errorf("render template: %w", err)
+		}
 
-	// Format the code
-	formatted, err := format.Source(code)
-	if err != nil {
-		// Write unformatted for debugging
-		if writeErr := os.WriteFile(outputFile+".unformatted", code, 0644); writeErr == nil {
-			fmt.Printf("  wrote unformatted code to %s.unformatted for debugging\n", outputFile)
+		// Format the code
+		formatted, err := format.Source(code)
+		if err != nil {
+			// Write unformatted for debugging
+			if g.opts.DryRun {
+				return fmt.Errorf("format source: %w", err)
+			}
+			if writeErr := os.WriteFile(out.file+".unformatted", code, 0644); writeErr == nil {
+				r.logf("  wrote unformatted code to %s.unformatted for debugging", out.file)
+			}
+			return fmt.Errorf("format source: %w", err)
+		}
+
+		// Write the file
+		if err := g.writeFile(r, out.file, formatted); err != nil {
+			return err
 		}
-		return fmt.Errorf("format source: %w", err)
 	}
 
-	// Write the file
-	return os.WriteFile(outputFile, formatted, 0644)
+	return nil
 }
 
-// renderTemplate renders the generated code template.
-func (g *Generator) renderTemplate(pkgName string, comp *ComponentInfo) ([]byte, error) {
-	tmpl, err := template.New("hx").Funcs(template.FuncMap{
+// templateFuncs returns the functions available to generated code templates,
+// including extension templates.
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
 		"title":        strings.Title,
 		"lower":        strings.ToLower,
 		"upper":        strings.ToUpper,
 		"camelToTitle": camelToTitle,
 		"encodeField":  encodeFieldCode,
 		"decodeField":  decodeFieldCode,
-	}).Parse(hxTemplate)
+		"bindField":    bindFieldCode,
+		"formValue":    formValueCode,
+		"checkField":   checkFieldCode,
+
+		"hasConstraints":    hasConstraints,
+		"handlerArgs":       handlerArgs,
+		"handlerType":       handlerType,
+		"runtimeSignatures": runtimeSignatures,
+		"isFormSig":         isFormSig,
+		"isRuntimeSig":      isRuntimeSig,
+		"readsRequest":      readsRequest,
+	}
+}
+
+// handlerArgs returns the arguments generated dispatch passes to a handler
+// with the given signature.
+func handlerArgs(sig HandlerSignature) string {
+	switch sig {
+	case HandlerSigCtxPropsRequest:
+		return "r.Context(), props, r"
+	case HandlerSigCtxPropsWriter:
+		return "r.Context(), props, w"
+	case HandlerSigCtxPropsWriterRequest:
+		return "r.Context(), props, w, r"
+	case HandlerSigCtxPropsForm:
+		return "r.Context(), props, form"
+	default:
+		return "r.Context(), props"
+	}
+}
+
+// handlerType returns the Go function type of a handler with the given
+// signature, for asserting handlers that are not component methods.
+func handlerType(propsType, formType string, sig HandlerSignature) string {
+	params := "context.Context, " + propsType
+	switch sig {
+	case HandlerSigCtxPropsRequest:
+		params += ", *http.Request"
+	case HandlerSigCtxPropsWriter:
+		params += ", http.ResponseWriter"
+	case HandlerSigCtxPropsWriterRequest:
+		params += ", http.ResponseWriter, *http.Request"
+	case HandlerSigCtxPropsForm:
+		params += ", " + formType
+	}
+	return "func(" + params + ") hxcmp.Result[" + propsType + "]"
+}
+
+// isFormSig reports whether a handler takes a form struct, which generated
+// dispatch binds from the request.
+func isFormSig(sig HandlerSignature) bool {
+	return sig == HandlerSigCtxPropsForm
+}
+
+// isRuntimeSig reports whether a handler's signature is only known when the
+// action is served.
+func isRuntimeSig(sig HandlerSignature) bool {
+	return sig == HandlerSigRuntime
+}
+
+// readsRequest reports whether a handler may read the request, so its test
+// client method takes form values. Runtime handlers may have any signature.
+func readsRequest(sig HandlerSignature) bool {
+	switch sig {
+	case HandlerSigCtxPropsRequest, HandlerSigCtxPropsWriterRequest, HandlerSigRuntime:
+		return true
+	}
+	return false
+}
+
+// runtimeSignatures lists the signatures tried, in order, for handlers whose
+// signature is only known when the action is served. Form handlers need a
+// generated binding, so they are not included.
+func runtimeSignatures() []HandlerSignature {
+	return []HandlerSignature{
+		HandlerSigCtxProps,
+		HandlerSigCtxPropsRequest,
+		HandlerSigCtxPropsWriter,
+		HandlerSigCtxPropsWriterRequest,
+	}
+}
+
+// renderTemplate renders a generated code template for a component.
+func (g *Generator) renderTemplate(text, pkgName string, comp *ComponentInfo) ([]byte, error) {
+	tmpl, err := template.New("hx").Funcs(templateFuncs()).Parse(text)
 	if err != nil {
 		return nil, err
 	}
@@ -313,6 +421,7 @@ import (
 
 // Compile-time interface compliance
 var _ hxcmp.HXComponent = (*{{.Component.TypeName}})(nil)
+var _ hxcmp.Generated = (*{{.Component.TypeName}})(nil)
 var _ hxcmp.Encodable = (*{{.Component.PropsType}})(nil)
 var _ hxcmp.Decodable = (*{{.Component.PropsType}})(nil)
 
@@ -329,11 +438,39 @@ func (p {{.Component.PropsType}}) HXEncode() map[string]any {
 }
 
 // HXDecode decodes props from a map.
+{{- if hasConstraints .Component.Props}}
+// Defaults are applied and constraints from hx tags are checked; violations
+// are returned as a *hxcmp.PropsError.
+{{- end}}
 func (p *{{.Component.PropsType}}) HXDecode(m map[string]any) error {
 	{{- range .Component.Props}}
 	{{decodeField .}}
 	{{- end}}
+	{{- if hasConstraints .Component.Props}}
+
+	var check hxcmp.PropsChecker
+	{{- range .Component.Props}}
+	{{- with checkField .}}
+	{{.}}
+	{{- end}}
+	{{- end}}
+	return check.Err()
+	{{- else}}
 	return nil
+	{{- end}}
+}
+
+// HXGenerated describes the source this code was generated from. The
+// registry uses it to detect actions added since the last 'hxcmp generate'.
+func (c *{{.Component.TypeName}}) HXGenerated() hxcmp.GeneratedInfo {
+	return hxcmp.GeneratedInfo{
+		SourceHash: "{{.Component.SourceHash}}",
+		Actions: []hxcmp.GeneratedAction{
+			{{- range .Component.Actions}}
+			{Name: "{{.Name}}", Method: "{{if eq .Method ""}}POST{{else}}{{.Method}}{{end}}"},
+			{{- end}}
+		},
+	}
 }
 
 // HXPrefix returns the component's URL prefix.
@@ -341,6 +478,22 @@ func (c *{{.Component.TypeName}}) HXPrefix() string {
 	return c.Prefix()
 }
 
+// HXRender hydrates props, checks the component's policies and returns its
+// Render output, for rendering it out of band with Result.OOB.
+func (c *{{.Component.TypeName}}) HXRender(ctx context.Context, props any) (templ.Component, error) {
+	p, err := c.OOBProps(props)
+	if err != nil {
+		return nil, err
+	}
+	if err := hxcmp.WrapHydrateError(c.Hydrate(ctx, &p)); err != nil {
+		return nil, err
+	}
+	if err := c.Authorize(ctx, "", p); err != nil {
+		return nil, err
+	}
+	return c.Render(ctx, p), nil
+}
+
 // RenderHydrated calls Hydrate then Render for initial page loads.
 // Use this in templates instead of Render when not going through HXServeHTTP.
 // If Hydrate returns an error, it returns an error component displaying the error.
@@ -353,7 +506,22 @@ func (c *{{.Component.TypeName}}) RenderHydrated(ctx context.Context, props {{.C
 
 // HXServeHTTP handles HTTP requests for this component.
 func (c *{{.Component.TypeName}}) HXServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Resolve the action before decoding so each stage is observed with it
+	var action string
+	path := strings.TrimPrefix(r.URL.Path, c.HXPrefix())
+	switch r.Method + " " + path {
+	case "GET /", "GET ":
+	{{- range .Component.Actions}}
+	case "{{if eq .Method ""}}POST{{else}}{{.Method}}{{end}} /{{.Name}}":
+		action = "{{.Name}}"
+	{{- end}}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
 	// Decode props from query string (GET) or form body (POST/PUT/DELETE)
+	start := time.Now()
 	encoded := r.URL.Query().Get("p")
 	if encoded == "" && r.Method != http.MethodGet {
 		// For non-GET, check form body
@@ -362,30 +530,44 @@ func (c *{{.Component.TypeName}}) HXServeHTTP(w http.ResponseWriter, r *http.Req
 		}
 	}
 	var props {{.Component.PropsType}}
+	var err error
 	if encoded != "" {
-		if err := c.Component.Encoder().Decode(encoded, c.IsSensitive(), &props); err != nil {
-			c.handleError(w, r, hxcmp.WrapDecodeError(err))
-			return
+		if err = c.Component.Encoder().Decode(encoded, c.IsSensitive(), &props); err != nil {
+			err = hxcmp.WrapDecodeError(err)
 		}
 	}
+	{{- if hasConstraints .Component.Props}} else {
+		// No props were sent: apply defaults and check required fields
+		err = props.HXDecode(nil)
+	}
+	{{- end}}
+	c.Observe(r, hxcmp.StageDecode, action, start, err)
+	if err != nil {
+		c.handleError(w, r, err)
+		return
+	}
+	c.LogProps(r.Context(), props)
+	if action == "" && c.ServeCached(w, r, props, false) {
+		return
+	}
 
 	// Run lifecycle: Hydrate
-	if err := c.Hydrate(r.Context(), &props); err != nil {
+	start = time.Now()
+	err = hxcmp.WrapHydrateError(c.Hydrate(r.Context(), &props))
+	c.Observe(r, hxcmp.StageHydrate, action, start, err)
+	if err != nil {
 		c.handleError(w, r, err)
 		return
 	}
 
 	// Route to handler
-	path := strings.TrimPrefix(r.URL.Path, c.HXPrefix())
-	switch r.Method + " " + path {
-	case "GET /", "GET ":
+	switch action {
+	case "":
 		c.serveRender(w, r, props)
 	{{- range .Component.Actions}}
-	case "{{if eq .Method ""}}POST{{else}}{{.Method}}{{end}} /{{.Name}}":
-		c.serve{{camelToTitle .Handler}}(w, r, props)
+	case "{{.Name}}":
+		c.serve{{camelToTitle .Name}}(w, r, props)
 	{{- end}}
-	default:
-		http.NotFound(w, r)
 	}
 }
 
@@ -401,7 +583,11 @@ func (c *{{.Component.TypeName}}) handleError(w http.ResponseWriter, r *http.Req
 		http.Error(w, "Not found", http.StatusNotFound)
 		return
 	}
-	if hxcmp.IsDecryptionError(err) {
+	if hxcmp.IsForbidden(err) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+	if hxcmp.IsDecryptionError(err) || hxcmp.IsValidationError(err) {
 		http.Error(w, "Bad request", http.StatusBadRequest)
 		return
 	}
@@ -409,29 +595,109 @@ func (c *{{.Component.TypeName}}) handleError(w http.ResponseWriter, r *http.Req
 }
 
 func (c *{{.Component.TypeName}}) serveRender(w http.ResponseWriter, r *http.Request, props {{.Component.PropsType}}) {
+	if err := c.Authorize(r.Context(), "", props); err != nil {
+		c.handleError(w, r, err)
+		return
+	}
+	if v, ok := any(c).(hxcmp.Versioner[{{.Component.PropsType}}]); ok {
+		version, err := v.Version(r.Context(), props)
+		if err != nil {
+			c.handleError(w, r, err)
+			return
+		}
+		if hxcmp.NotModified(w, r, hxcmp.ETag([]byte(version))) {
+			return
+		}
+	}
+	if c.ServeCached(w, r, props, true) {
+		return
+	}
+	c.render(w, r, "", 0, props, nil)
+}
+
+// render writes the component's Render output, followed by any out-of-band
+// swaps, with status, or 200 if it is 0. Render failures are passed to
+// OnError unless the component streams.
+func (c *{{.Component.TypeName}}) render(w http.ResponseWriter, r *http.Request, action string, status int, props {{.Component.PropsType}}, oob []hxcmp.OOBSwap) {
 	tmpl := c.Render(r.Context(), props)
+	if action == "" {
+		tmpl = c.CacheRender(r, props, tmpl)
+	}
+	tmpl, err := c.WithOOB(r.Context(), tmpl, oob)
+	if err != nil {
+		c.handleError(w, r, err)
+		return
+	}
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	tmpl.Render(r.Context(), w)
+	if err := c.WriteRender(w, r, action, status, tmpl); err != nil {
+		c.handleError(w, r, err)
+	}
 }
 
 {{range .Component.Actions}}
-func (c *{{$.Component.TypeName}}) serve{{camelToTitle .Handler}}(w http.ResponseWriter, r *http.Request, props {{$.Component.PropsType}}) {
-	{{- if eq .Signature 0}}
-	result := c.{{.Handler}}(r.Context(), props)
-	{{- else if eq .Signature 2}}
-	result := c.{{.Handler}}(r.Context(), props, w)
+func (c *{{$.Component.TypeName}}) serve{{camelToTitle .Name}}(w http.ResponseWriter, r *http.Request, props {{$.Component.PropsType}}) {
+	if err := c.Authorize(r.Context(), "{{.Name}}", props); err != nil {
+		c.handleError(w, r, err)
+		return
+	}
+	{{- if isFormSig .Signature}}
+	form, err := c.bind{{.FormType}}(r)
+	if err != nil {
+		c.handleError(w, r, err)
+		return
+	}
+	{{- end}}
+	{{- if isRuntimeSig .Signature}}
+	// The handler's declaration isn't visible to the generator
+	var result hxcmp.Result[{{$.Component.PropsType}}]
+	start := time.Now()
+	switch h := c.Handler("{{.Name}}").(type) {
+	{{- range $sig := runtimeSignatures}}
+	case {{handlerType $.Component.PropsType "" $sig}}:
+		result = h({{handlerArgs $sig}})
+	{{- end}}
+	default:
+		c.handleError(w, r, hxcmp.HandlerTypeError("{{.Name}}", h))
+		return
+	}
+	{{- else if eq .HandlerKind "method"}}
+	start := time.Now()
+	result := c.{{.Handler}}({{handlerArgs .Signature}})
 	{{- else}}
-	result := c.{{.Handler}}(r.Context(), props, r)
+	h, ok := c.Handler("{{.Name}}").({{handlerType $.Component.PropsType .FormType .Signature}})
+	if !ok {
+		c.handleError(w, r, hxcmp.HandlerTypeError("{{.Name}}", c.Handler("{{.Name}}")))
+		return
+	}
+	start := time.Now()
+	result := h({{handlerArgs .Signature}})
 	{{- end}}
-	c.handleResult(w, r, result)
+	c.Observe(r, hxcmp.StageHandler, "{{.Name}}", start, result.GetErr())
+	c.handleResult(w, r, "{{.Name}}", result)
+}
+{{end}}
+{{range .Component.Forms}}
+// bind{{.TypeName}} parses the request form into a {{.TypeName}}.
+// Values that fail to parse are reported as a *hxcmp.ValidationError.
+func (c *{{$.Component.TypeName}}) bind{{.TypeName}}(r *http.Request) ({{.TypeName}}, error) {
+	var f {{.TypeName}}
+	b, err := hxcmp.NewFormBinder(r)
+	if err != nil {
+		return f, err
+	}
+	{{- range .Fields}}
+	{{bindField .}}
+	{{- end}}
+	return f, b.Err()
 }
 {{end}}
 
-func (c *{{.Component.TypeName}}) handleResult(w http.ResponseWriter, r *http.Request, result hxcmp.Result[{{.Component.PropsType}}]) {
+func (c *{{.Component.TypeName}}) handleResult(w http.ResponseWriter, r *http.Request, action string, result hxcmp.Result[{{.Component.PropsType}}]) {
 	if err := result.GetErr(); err != nil {
 		c.handleError(w, r, err)
 		return
 	}
+	c.InvalidateCache(result.GetInvalidate()...)
 
 	// Set all headers BEFORE WriteHeader to ensure they are sent
 	for k, v := range result.GetHeaders() {
@@ -455,12 +721,7 @@ func (c *{{.Component.TypeName}}) handleResult(w http.ResponseWriter, r *http.Re
 		return
 	}
 	// Auto-render with updated props
-	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	if status := result.GetStatus(); status != 0 {
-		w.WriteHeader(status)
-	}
-	tmpl := c.Render(r.Context(), result.GetProps())
-	tmpl.Render(r.Context(), w)
+	c.render(w, r, action, result.GetStatus(), result.GetProps(), result.GetOOB())
 }
 
 // WireRender returns HTMX attributes for the default render (GET) endpoint.
@@ -475,6 +736,42 @@ func (c *{{$.Component.TypeName}}) Wire{{camelToTitle .Name}}(props {{$.Componen
 	path, encoded := c.buildActionURL("{{.Name}}", props)
 	return hxcmp.WireAttrs(path, "{{if eq .Method ""}}POST{{else}}{{.Method}}{{end}}", encoded)
 }
+
+// Wire{{camelToTitle .Name}}For returns Wire{{camelToTitle .Name}} if the user in ctx may run the
+// "{{.Name}}" action, and otherwise empty attributes or, after
+// DisableWhenDenied, a disabled marker.
+func (c *{{$.Component.TypeName}}) Wire{{camelToTitle .Name}}For(ctx context.Context, props {{$.Component.PropsType}}) templ.Attributes {
+	if attrs, denied := c.DeniedAttrs(ctx, "{{.Name}}", props); denied {
+		return attrs
+	}
+	return c.Wire{{camelToTitle .Name}}(props)
+}
+{{end}}
+
+// URLRender returns the URL of the default render (GET) endpoint with props
+// encoded in the query string.
+func (c *{{.Component.TypeName}}) URLRender(props {{.Component.PropsType}}) string {
+	path, encoded := c.buildActionURL("", props)
+	return hxcmp.ActionURL(path, encoded)
+}
+
+// AbsoluteURLRender returns URLRender joined with base (e.g., "https://example.com").
+func (c *{{.Component.TypeName}}) AbsoluteURLRender(base string, props {{.Component.PropsType}}) string {
+	return hxcmp.AbsoluteURL(base, c.URLRender(props))
+}
+
+{{range .Component.Actions}}
+// URL{{camelToTitle .Name}} returns the URL of the "{{.Name}}" action with props encoded in
+// the query string. The action must still be requested with {{if eq .Method ""}}POST{{else}}{{.Method}}{{end}}.
+func (c *{{$.Component.TypeName}}) URL{{camelToTitle .Name}}(props {{$.Component.PropsType}}) string {
+	path, encoded := c.buildActionURL("{{.Name}}", props)
+	return hxcmp.ActionURL(path, encoded)
+}
+
+// AbsoluteURL{{camelToTitle .Name}} returns URL{{camelToTitle .Name}} joined with base (e.g., "https://example.com").
+func (c *{{$.Component.TypeName}}) AbsoluteURL{{camelToTitle .Name}}(base string, props {{$.Component.PropsType}}) string {
+	return hxcmp.AbsoluteURL(base, c.URL{{camelToTitle .Name}}(props))
+}
 {{end}}
 
 func (c *{{.Component.TypeName}}) buildActionURL(action string, props {{.Component.PropsType}}) (path string, encoded string) {
@@ -499,3 +796,74 @@ func (c *{{.Component.TypeName}}) buildActionURL(action string, props {{.Compone
 // Ensure time import is used
 var _ = time.RFC3339
 `
+
+const hxTestTemplate = `// Code generated by hxcmp. DO NOT EDIT.
+// Source: {{.Component.SourceFile}}
+
+//go:build !hxcmp_ignore
+
+package {{.Package}}
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/pthm/hxcmp"
+)
+
+// {{.Component.TypeName}}TestClient sends requests to a {{.Component.TypeName}} in tests, with one
+// typed method per action. Props are encoded with hxcmp.TestKey.
+type {{.Component.TypeName}}TestClient struct {
+	*hxcmp.TestClient
+	c *{{.Component.TypeName}}
+}
+
+// New{{.Component.TypeName}}TestClient registers c with a test registry and returns a client for it.
+func New{{.Component.TypeName}}TestClient(c *{{.Component.TypeName}}) *{{.Component.TypeName}}TestClient {
+	return &{{.Component.TypeName}}TestClient{TestClient: hxcmp.NewTestClient(c), c: c}
+}
+
+// Render requests the default render (GET) endpoint.
+func (t *{{.Component.TypeName}}TestClient) Render(props {{.Component.PropsType}}) *hxcmp.TestResult {
+	return t.TestClient.Do(http.MethodGet, t.c.URLRender(props), nil)
+}
+
+{{range .Component.Actions}}
+{{- $method := printf "%q" (or .Method "POST")}}
+{{- if readsRequest .Signature}}
+// {{camelToTitle .Name}} requests the "{{.Name}}" action with the given form values.
+func (t *{{$.Component.TypeName}}TestClient) {{camelToTitle .Name}}(props {{$.Component.PropsType}}, form url.Values) *hxcmp.TestResult {
+	return t.TestClient.Do({{$method}}, t.c.URL{{camelToTitle .Name}}(props), form)
+}
+{{- else if isFormSig .Signature}}
+// {{camelToTitle .Name}} requests the "{{.Name}}" action with form submitted as its fields.
+func (t *{{$.Component.TypeName}}TestClient) {{camelToTitle .Name}}(props {{$.Component.PropsType}}, form {{.FormType}}) *hxcmp.TestResult {
+	return t.TestClient.Do({{$method}}, t.c.URL{{camelToTitle .Name}}(props), t.encode{{.FormType}}(form))
+}
+{{- else}}
+// {{camelToTitle .Name}} requests the "{{.Name}}" action.
+func (t *{{$.Component.TypeName}}TestClient) {{camelToTitle .Name}}(props {{$.Component.PropsType}}) *hxcmp.TestResult {
+	return t.TestClient.Do({{$method}}, t.c.URL{{camelToTitle .Name}}(props), nil)
+}
+{{- end}}
+{{end}}
+{{range .Component.Forms}}
+// encode{{.TypeName}} converts a {{.TypeName}} to the form values its binding reads.
+func (t *{{$.Component.TypeName}}TestClient) encode{{.TypeName}}(f {{.TypeName}}) url.Values {
+	v := make(url.Values)
+	{{- range .Fields}}
+	{{formValue .}}
+	{{- end}}
+	return v
+}
+{{end}}
+
+// Ensure imports are used
+var (
+	_ url.Values
+	_ = strconv.Itoa
+	_ = time.RFC3339
+)
+`
@@ -0,0 +1,106 @@
+package generator
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Logger receives human-readable progress output, one line per call.
+// *log.Logger satisfies Logger.
+type Logger interface {
+	Printf(format string, args ...any)
+}
+
+// FileStatus is what happened to a generated file.
+type FileStatus string
+
+const (
+	// FileWritten means the file was created or its contents changed.
+	FileWritten FileStatus = "written"
+	// FileSkipped means the file already held the generated contents, or
+	// its package was skipped because its inputs are unchanged.
+	FileSkipped FileStatus = "skipped"
+	// FileRemoved means a stale generated file was deleted.
+	FileRemoved FileStatus = "removed"
+)
+
+// Severity classifies a diagnostic.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Report describes the result of a Generate run. Packages are listed in the
+// order they were given or found, regardless of the order they completed.
+type Report struct {
+	DryRun      bool            `json:"dry_run"`
+	Packages    []PackageReport `json:"packages"`
+	Diagnostics []Diagnostic    `json:"diagnostics"` // All diagnostics, in package order
+}
+
+// PackageReport describes the result of generating a single package.
+type PackageReport struct {
+	Dir  string `json:"dir"`
+	Name string `json:"name,omitempty"`
+
+	// Skipped is true when the package's inputs were unchanged since the
+	// last run. Components are still listed for skipped packages.
+	Skipped bool `json:"skipped"`
+
+	Components  []ComponentReport `json:"components"`
+	Files       []FileReport      `json:"files"`
+	Diagnostics []Diagnostic      `json:"diagnostics"`
+}
+
+// ComponentReport describes a component found in a package.
+type ComponentReport struct {
+	Type       string   `json:"type"`
+	SourceFile string   `json:"source_file"`
+	Actions    []string `json:"actions"`
+}
+
+// FileReport describes a generated file.
+type FileReport struct {
+	Path   string     `json:"path"`
+	Status FileStatus `json:"status"`
+}
+
+// Diagnostic is an error or warning found while generating a package.
+type Diagnostic struct {
+	Package  string   `json:"package"`
+	File     string   `json:"file,omitempty"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+// HasErrors reports whether any diagnostic is an error.
+func (r *Report) HasErrors() bool {
+	for _, d := range r.Diagnostics {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// newComponentReport summarizes a ComponentInfo for a report.
+func newComponentReport(comp *ComponentInfo) ComponentReport {
+	cr := ComponentReport{
+		Type:       comp.TypeName,
+		SourceFile: comp.SourceFile,
+		Actions:    []string{},
+	}
+	for _, a := range comp.Actions {
+		cr.Actions = append(cr.Actions, a.Name)
+	}
+	return cr
+}
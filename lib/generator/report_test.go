@@ -0,0 +1,215 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fileStatuses maps generated file base names to their reported status.
+func fileStatuses(pkg PackageReport) map[string]FileStatus {
+	m := make(map[string]FileStatus)
+	for _, f := range pkg.Files {
+		m[filepath.Base(f.Path)] = f.Status
+	}
+	return m
+}
+
+func TestGenerateReport(t *testing.T) {
+	dir := writeManifestPackage(t)
+
+	var logs bytes.Buffer
+	g := New(Options{Logger: log.New(&logs, "", 0)})
+
+	report, err := g.GenerateReport(dir)
+	if err != nil {
+		t.Fatalf("GenerateReport() error = %v", err)
+	}
+	if len(report.Packages) != 1 {
+		t.Fatalf("len(Packages) = %d, want 1", len(report.Packages))
+	}
+
+	pkg := report.Packages[0]
+	if pkg.Dir != dir || pkg.Name != "components" || pkg.Skipped {
+		t.Errorf("package = %+v", pkg)
+	}
+	if len(pkg.Components) != 1 || pkg.Components[0].Type != "ItemView" ||
+		strings.Join(pkg.Components[0].Actions, ",") != "delete,save" {
+		t.Errorf("components = %+v", pkg.Components)
+	}
+	want := map[string]FileStatus{
+		"item_hx.go":      FileWritten,
+		"item_hx_test.go": FileWritten,
+		helpersFile:       FileWritten,
+	}
+	if got := fileStatuses(pkg); !equalStatuses(got, want) {
+		t.Errorf("files = %v, want %v", got, want)
+	}
+	if !strings.Contains(logs.String(), "generating "+filepath.Join(dir, "item_hx.go")) {
+		t.Errorf("logger output = %q", logs.String())
+	}
+
+	// An unchanged package is skipped, with its files reported as skipped
+	report, err = g.GenerateReport(dir)
+	if err != nil {
+		t.Fatalf("GenerateReport() error = %v", err)
+	}
+	pkg = report.Packages[0]
+	want = map[string]FileStatus{
+		"item_hx.go":      FileSkipped,
+		"item_hx_test.go": FileSkipped,
+		helpersFile:       FileSkipped,
+	}
+	if !pkg.Skipped || !equalStatuses(fileStatuses(pkg), want) {
+		t.Errorf("second run: skipped = %v, files = %v", pkg.Skipped, fileStatuses(pkg))
+	}
+	if pkg.Name != "components" || len(pkg.Components) != 1 || pkg.Components[0].Type != "ItemView" ||
+		strings.Join(pkg.Components[0].Actions, ",") != "delete,save" {
+		t.Errorf("second run: name = %q, components = %+v", pkg.Name, pkg.Components)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	var decoded Report
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if !strings.Contains(buf.String(), `"status": "skipped"`) {
+		t.Errorf("JSON missing file status:\n%s", buf.String())
+	}
+}
+
+func equalStatuses(a, b map[string]FileStatus) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if b[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func TestGenerateReportRemovesStaleFiles(t *testing.T) {
+	dir := writeManifestPackage(t)
+	other := strings.NewReplacer("ItemView", "OtherView", "ItemProps", "OtherProps", "type Item struct{}", "").Replace(manifestSource)
+	if err := os.WriteFile(filepath.Join(dir, "other.go"), []byte(other), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	g := New(Options{Logger: log.New(&bytes.Buffer{}, "", 0)})
+	if _, err := g.GenerateReport(dir); err != nil {
+		t.Fatalf("GenerateReport() error = %v", err)
+	}
+
+	if err := os.Remove(filepath.Join(dir, "other.go")); err != nil {
+		t.Fatal(err)
+	}
+	report, err := g.GenerateReport(dir)
+	if err != nil {
+		t.Fatalf("GenerateReport() error = %v", err)
+	}
+
+	statuses := fileStatuses(report.Packages[0])
+	for _, name := range []string{"other_hx.go", "other_hx_test.go"} {
+		if statuses[name] != FileRemoved {
+			t.Errorf("%s status = %q, want removed", name, statuses[name])
+		}
+		if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Errorf("%s not removed", name)
+		}
+	}
+	if statuses["item_hx.go"] != FileSkipped || statuses[helpersFile] != FileWritten {
+		t.Errorf("files = %v", statuses)
+	}
+}
+
+func TestGenerateReportDiagnostics(t *testing.T) {
+	root := t.TempDir()
+
+	// Handler declared in another file: generated, with a warning
+	warn := filepath.Join(root, "warn")
+	split := strings.Replace(manifestSource, `func (c *ItemView) handleSave(ctx context.Context, props ItemProps) hxcmp.Result[ItemProps] {
+	return hxcmp.OK(props)
+}`, "", 1)
+	writeFiles(t, warn, map[string]string{
+		"item.go": split,
+		"save.go": `package components
+
+import (
+	"context"
+
+	"github.com/pthm/hxcmp"
+)
+
+func (c *ItemView) handleSave(ctx context.Context, props ItemProps) hxcmp.Result[ItemProps] {
+	return hxcmp.OK(props)
+}
+`,
+	})
+
+	// Missing form type: an error
+	broken := filepath.Join(root, "broken")
+	writeFiles(t, broken, map[string]string{
+		"item.go": strings.Replace(manifestSource,
+			"handleSave(ctx context.Context, props ItemProps)",
+			"handleSave(ctx context.Context, props ItemProps, form MissingForm)", 1),
+	})
+
+	g := New(Options{Logger: log.New(&bytes.Buffer{}, "", 0)})
+	report, err := g.GenerateReport(broken, warn)
+	if err == nil || !report.HasErrors() {
+		t.Fatalf("GenerateReport() error = %v, HasErrors = %v", err, report.HasErrors())
+	}
+
+	if len(report.Diagnostics) != 2 {
+		t.Fatalf("diagnostics = %+v, want 2", report.Diagnostics)
+	}
+	errDiag, warnDiag := report.Diagnostics[0], report.Diagnostics[1]
+	if errDiag.Package != broken || errDiag.Severity != SeverityError || !strings.Contains(errDiag.Message, "MissingForm") {
+		t.Errorf("error diagnostic = %+v", errDiag)
+	}
+	if warnDiag.Package != warn || warnDiag.Severity != SeverityWarning ||
+		!strings.Contains(warnDiag.Message, "handleSave") || filepath.Base(warnDiag.File) != "item.go" {
+		t.Errorf("warning diagnostic = %+v", warnDiag)
+	}
+
+	// The warning doesn't stop generation
+	if _, err := os.Stat(filepath.Join(warn, "item_hx.go")); err != nil {
+		t.Errorf("package with warning not generated: %v", err)
+	}
+}
+
+func TestGenerateReportDryRun(t *testing.T) {
+	dir := writeManifestPackage(t)
+
+	report, err := New(Options{DryRun: true, Logger: log.New(&bytes.Buffer{}, "", 0)}).GenerateReport(dir)
+	if err != nil {
+		t.Fatalf("GenerateReport() error = %v", err)
+	}
+	if !report.DryRun || fileStatuses(report.Packages[0])["item_hx.go"] != FileWritten {
+		t.Errorf("report = %+v", report)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "item_hx.go")); !os.IsNotExist(err) {
+		t.Error("dry run wrote item_hx.go")
+	}
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
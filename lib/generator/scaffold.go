@@ -0,0 +1,552 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// ScaffoldOptions configures the component produced by Scaffold.
+type ScaffoldOptions struct {
+	// Dir is the package directory the component is created in.
+	Dir string
+	// Name is the component name (e.g., "taskdetail" or "task-detail").
+	Name string
+	// Actions lists the actions to register, in order.
+	Actions []ScaffoldAction
+	// Sensitive marks the component's props as encrypted.
+	Sensitive bool
+}
+
+// ScaffoldAction describes an action created by Scaffold.
+type ScaffoldAction struct {
+	Name   string // Action name (e.g., "edit")
+	Method string // HTTP method (defaults to POST)
+}
+
+// ParseScaffoldActions parses an action list such as "edit,delete:DELETE".
+//
+// Each entry is an action name optionally followed by a colon and an HTTP
+// method. Entries without a method default to POST.
+func ParseScaffoldActions(spec string) ([]ScaffoldAction, error) {
+	var actions []ScaffoldAction
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, method, _ := strings.Cut(part, ":")
+		if !isIdentifier(name) {
+			return nil, fmt.Errorf("invalid action name %q", name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate action %q", name)
+		}
+		seen[name] = true
+
+		method = strings.ToUpper(method)
+		if method == "" {
+			method = "POST"
+		}
+		if _, ok := methodConstants[method]; !ok {
+			return nil, fmt.Errorf("action %q: unsupported method %q", name, method)
+		}
+
+		actions = append(actions, ScaffoldAction{Name: name, Method: method})
+	}
+
+	return actions, nil
+}
+
+// methodConstants maps HTTP methods to their net/http constant names.
+var methodConstants = map[string]string{
+	"GET":    "http.MethodGet",
+	"POST":   "http.MethodPost",
+	"PUT":    "http.MethodPut",
+	"PATCH":  "http.MethodPatch",
+	"DELETE": "http.MethodDelete",
+}
+
+// Scaffold creates a new component in opts.Dir following the conventions of
+// the todo example: a component source file, a .templ template, a test using
+// hxcmp.TestRender, and registration in the package's registry.go.
+//
+// Existing files are never overwritten. Every file, including the registry
+// edit, is prepared before anything is written, so a registry that can't be
+// updated leaves the package untouched and the command can be retried. After
+// the files are written, code generation runs for the package so the new
+// component compiles once 'templ generate' has been run.
+func (g *Generator) Scaffold(opts ScaffoldOptions) error {
+	data, err := g.scaffoldData(opts)
+	if err != nil {
+		return err
+	}
+
+	files := []struct {
+		name  string
+		tmpl  string
+		gofmt bool
+	}{
+		{data.FileBase + ".go", scaffoldComponentTemplate, true},
+		{data.FileBase + ".templ", scaffoldTemplTemplate, false},
+		{data.FileBase + "_test.go", scaffoldTestTemplate, true},
+	}
+
+	for _, f := range files {
+		path := filepath.Join(opts.Dir, f.name)
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("%s already exists", path)
+		}
+	}
+
+	var writes []scaffoldFile
+	for _, f := range files {
+		code, err := executeScaffoldTemplate(f.tmpl, data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.name, err)
+		}
+		if f.gofmt {
+			if code, err = format.Source(code); err != nil {
+				return fmt.Errorf("format %s: %w", f.name, err)
+			}
+		}
+		writes = append(writes, scaffoldFile{path: filepath.Join(opts.Dir, f.name), code: code})
+	}
+
+	registry, err := scaffoldRegistry(opts.Dir, data)
+	if err != nil {
+		return err
+	}
+	writes = append(writes, registry)
+
+	if !g.opts.DryRun {
+		if err := os.MkdirAll(opts.Dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	for i, f := range writes {
+		if f.update {
+			g.logger.Printf("updating %s", f.path)
+		} else {
+			g.logger.Printf("creating %s", f.path)
+		}
+		if g.opts.DryRun {
+			continue
+		}
+		if err := os.WriteFile(f.path, f.code, 0644); err != nil {
+			// Remove what this run created so it can be retried
+			for _, created := range writes[:i] {
+				if !created.update {
+					os.Remove(created.path)
+				}
+			}
+			return err
+		}
+	}
+
+	if g.opts.DryRun {
+		return nil
+	}
+
+	return g.Generate(opts.Dir)
+}
+
+// scaffoldFile is a file written by Scaffold.
+type scaffoldFile struct {
+	path   string
+	code   []byte
+	update bool // Replaces an existing file rather than creating one
+}
+
+// scaffoldData holds the template data for a scaffolded component.
+type scaffoldData struct {
+	Package      string
+	Name         string // Component name passed to hxcmp.New
+	TypeName     string // e.g., "TaskDetail"
+	PropsType    string // e.g., "TaskDetailProps"
+	TemplateFunc string // e.g., "taskDetailTemplate"
+	FileBase     string // e.g., "taskdetail"
+	Sensitive    bool
+	Actions      []scaffoldActionData
+}
+
+type scaffoldActionData struct {
+	Name      string
+	Method    string
+	MethodRef string // e.g., "http.MethodDelete"; empty for POST
+	Handler   string // e.g., "handleEdit"
+	Wire      string // e.g., "WireEdit"
+	Label     string // e.g., "Edit"
+}
+
+// scaffoldData validates opts and builds the template data.
+func (g *Generator) scaffoldData(opts ScaffoldOptions) (*scaffoldData, error) {
+	words := splitWords(opts.Name)
+	if len(words) == 0 {
+		return nil, fmt.Errorf("invalid component name %q", opts.Name)
+	}
+
+	typeName := ""
+	for _, w := range words {
+		typeName += string(unicode.ToUpper(rune(w[0]))) + w[1:]
+	}
+	if !isIdentifier(typeName) {
+		return nil, fmt.Errorf("invalid component name %q", opts.Name)
+	}
+
+	pkgName, err := packageName(opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &scaffoldData{
+		Package:      pkgName,
+		Name:         strings.ToLower(strings.Join(words, "")),
+		TypeName:     typeName,
+		PropsType:    typeName + "Props",
+		TemplateFunc: string(unicode.ToLower(rune(typeName[0]))) + typeName[1:] + "Template",
+		FileBase:     strings.ToLower(strings.Join(words, "")),
+		Sensitive:    opts.Sensitive,
+	}
+
+	for _, a := range opts.Actions {
+		method := a.Method
+		if method == "" {
+			method = "POST"
+		}
+		ad := scaffoldActionData{
+			Name:    a.Name,
+			Method:  method,
+			Handler: "handle" + camelToTitle(a.Name),
+			Wire:    "Wire" + camelToTitle(a.Name),
+			Label:   camelToTitle(a.Name),
+		}
+		if method != "POST" {
+			ad.MethodRef = methodConstants[method]
+		}
+		data.Actions = append(data.Actions, ad)
+	}
+
+	return data, nil
+}
+
+// scaffoldRegistry returns the package's registry.go with the new component
+// added to its Init function, or a new registry.go if it doesn't exist.
+func scaffoldRegistry(dir string, data *scaffoldData) (scaffoldFile, error) {
+	path := filepath.Join(dir, "registry.go")
+	ctor := "New" + data.TypeName + "()"
+
+	src, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		code, err := executeScaffoldTemplate(scaffoldRegistryTemplate, data)
+		if err != nil {
+			return scaffoldFile{}, fmt.Errorf("registry.go: %w", err)
+		}
+		if code, err = format.Source(code); err != nil {
+			return scaffoldFile{}, fmt.Errorf("format registry.go: %w", err)
+		}
+		return scaffoldFile{path: path, code: code}, nil
+	}
+	if err != nil {
+		return scaffoldFile{}, err
+	}
+
+	updated, err := insertRegistration(src, ctor)
+	if err != nil {
+		return scaffoldFile{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return scaffoldFile{path: path, code: updated, update: true}, nil
+}
+
+// insertRegistration appends ctor to the first Add call in src's Init
+// function whose receiver is Init's *hxcmp.Registry parameter.
+func insertRegistration(src []byte, ctor string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "registry.go", src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSuffix(ctor, "()")
+	initFn, reg := registryInit(file)
+	if initFn == nil {
+		return nil, fmt.Errorf("no Init(reg *hxcmp.Registry) function found; register %s manually", name)
+	}
+
+	var addCall *ast.CallExpr
+	ast.Inspect(initFn.Body, func(n ast.Node) bool {
+		if addCall != nil {
+			return false
+		}
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Add" {
+			if recv, ok := sel.X.(*ast.Ident); ok && recv.Name == reg {
+				addCall = call
+				return false
+			}
+		}
+		return true
+	})
+	if addCall == nil {
+		return nil, fmt.Errorf("no %s.Add call found in Init; register %s manually", reg, name)
+	}
+
+	var buf bytes.Buffer
+	rparen := fset.Position(addCall.Rparen).Offset
+	switch {
+	case len(addCall.Args) == 0:
+		buf.Write(src[:rparen])
+		buf.WriteString(ctor)
+	case addCall.Ellipsis.IsValid():
+		return nil, fmt.Errorf("%s.Add uses a variadic slice; register %s manually", reg, name)
+	default:
+		// Insert after the last argument so trailing commas and comments
+		// on the closing line are preserved; gofmt fixes the layout.
+		last := fset.Position(addCall.Args[len(addCall.Args)-1].End()).Offset
+		buf.Write(src[:last])
+		if fset.Position(addCall.Rparen).Line > fset.Position(addCall.Args[len(addCall.Args)-1].End()).Line {
+			buf.WriteString(",\n" + ctor)
+		} else {
+			buf.WriteString(", " + ctor)
+		}
+		rparen = last
+	}
+	buf.Write(src[rparen:])
+
+	return format.Source(buf.Bytes())
+}
+
+// registryInit returns the file's Init function and the name of its
+// *hxcmp.Registry parameter, or nil if there is no such function.
+func registryInit(file *ast.File) (*ast.FuncDecl, string) {
+	imports := fileImports(file)
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || fn.Name.Name != "Init" || fn.Body == nil {
+			continue
+		}
+		for _, field := range fn.Type.Params.List {
+			star, ok := field.Type.(*ast.StarExpr)
+			if !ok {
+				continue
+			}
+			sel, ok := star.X.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != "Registry" {
+				continue
+			}
+			if pkg, ok := sel.X.(*ast.Ident); ok && imports[pkg.Name] && len(field.Names) > 0 {
+				return fn, field.Names[0].Name
+			}
+		}
+	}
+	return nil, ""
+}
+
+// packageName returns the Go package name used in dir, falling back to the
+// directory name when the directory has no Go files yet.
+func packageName(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+
+	fset := token.NewFileSet()
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.PackageClauseOnly)
+		if err != nil {
+			continue
+		}
+		return file.Name.Name, nil
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	name := strings.ToLower(strings.Join(splitWords(filepath.Base(abs)), ""))
+	if !isIdentifier(name) {
+		return "", fmt.Errorf("cannot derive package name from %q", dir)
+	}
+	return name, nil
+}
+
+// splitWords splits a name on '-', '_' and lower-to-upper case boundaries.
+// "task-detail", "task_detail" and "taskDetail" all yield ["task", "Detail"]
+// (the first letter of each word is preserved as written).
+func splitWords(s string) []string {
+	var words []string
+	var cur []rune
+	flush := func() {
+		if len(cur) > 0 {
+			words = append(words, string(cur))
+			cur = nil
+		}
+	}
+	for i, r := range s {
+		switch {
+		case r == '-' || r == '_' || r == ' ':
+			flush()
+		case unicode.IsUpper(r) && i > 0 && len(cur) > 0 && unicode.IsLower(cur[len(cur)-1]):
+			flush()
+			cur = append(cur, r)
+		default:
+			cur = append(cur, r)
+		}
+	}
+	flush()
+	return words
+}
+
+// isIdentifier reports whether s is a valid Go identifier.
+func isIdentifier(s string) bool {
+	if s == "" || token.IsKeyword(s) {
+		return false
+	}
+	for i, r := range s {
+		if r == '_' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r)) {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// executeScaffoldTemplate renders a scaffold template with data.
+func executeScaffoldTemplate(text string, data *scaffoldData) ([]byte, error) {
+	tmpl, err := template.New("scaffold").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+const scaffoldComponentTemplate = `package {{.Package}}
+
+import (
+	"context"
+	{{- if .Actions}}
+	"net/http"
+	{{- end}}
+
+	"github.com/a-h/templ"
+	"github.com/pthm/hxcmp"
+)
+
+// {{.PropsType}} defines the props for the {{.TypeName}} component.
+type {{.PropsType}} struct {
+	ID string ` + "`" + `hx:"id"` + "`" + `
+}
+
+// {{.TypeName}} is the {{.Name}} component.
+type {{.TypeName}} struct {
+	*hxcmp.Component[{{.PropsType}}]
+}
+
+// New{{.TypeName}} creates a new {{.TypeName}} component.
+func New{{.TypeName}}() *{{.TypeName}} {
+	c := &{{.TypeName}}{
+		Component: hxcmp.New[{{.PropsType}}]("{{.Name}}"),
+	}
+	{{- if .Sensitive}}
+	c.Sensitive()
+	{{- end}}
+	{{- range .Actions}}
+	c.Action("{{.Name}}", c.{{.Handler}}){{if .MethodRef}}.Method({{.MethodRef}}){{end}}
+	{{- end}}
+	return c
+}
+
+// Hydrate loads data for the component.
+func (c *{{.TypeName}}) Hydrate(ctx context.Context, props *{{.PropsType}}) error {
+	return nil
+}
+
+// Render produces the HTML output.
+func (c *{{.TypeName}}) Render(ctx context.Context, props {{.PropsType}}) templ.Component {
+	return {{.TemplateFunc}}(c, props)
+}
+{{range .Actions}}
+// {{.Handler}} handles the "{{.Name}}" action.
+func (c *{{$.TypeName}}) {{.Handler}}(ctx context.Context, props {{$.PropsType}}, r *http.Request) hxcmp.Result[{{$.PropsType}}] {
+	return hxcmp.OK(props)
+}
+{{end}}`
+
+const scaffoldTemplTemplate = `package {{.Package}}
+
+templ {{.TemplateFunc}}(c *{{.TypeName}}, props {{.PropsType}}) {
+	<div
+		id="{{.Name}}"
+		class="{{.Name}}"
+		{ c.WireRender(props)... }
+		hx-target="#{{.Name}}"
+		hx-swap="outerHTML"
+	>
+		<p>{ props.ID }</p>
+		{{- range .Actions}}
+		<button
+			{ c.{{.Wire}}(props)... }
+			hx-target="#{{$.Name}}"
+			hx-swap="outerHTML"
+		>
+			{{.Label}}
+		</button>
+		{{- end}}
+	</div>
+}
+`
+
+const scaffoldTestTemplate = `package {{.Package}}
+
+import (
+	"testing"
+
+	"github.com/pthm/hxcmp"
+)
+
+func Test{{.TypeName}}Render(t *testing.T) {
+	comp := New{{.TypeName}}()
+
+	result, err := hxcmp.TestRender(comp, {{.PropsType}}{ID: "1"})
+	if err != nil {
+		t.Fatalf("TestRender() error = %v", err)
+	}
+
+	if !result.HTMLContains(` + "`" + `id="{{.Name}}"` + "`" + `) {
+		t.Errorf("HTML does not contain component root: %s", result.HTML)
+	}
+}
+`
+
+const scaffoldRegistryTemplate = `package {{.Package}}
+
+import "github.com/pthm/hxcmp"
+
+// Init registers all components with the registry.
+func Init(reg *hxcmp.Registry) {
+	reg.Add(New{{.TypeName}}())
+}
+`
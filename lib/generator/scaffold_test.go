@@ -0,0 +1,236 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseScaffoldActions(t *testing.T) {
+	actions, err := ParseScaffoldActions("edit, delete:DELETE,raw:get")
+	if err != nil {
+		t.Fatalf("ParseScaffoldActions() error = %v", err)
+	}
+
+	expected := []ScaffoldAction{
+		{Name: "edit", Method: "POST"},
+		{Name: "delete", Method: "DELETE"},
+		{Name: "raw", Method: "GET"},
+	}
+	if len(actions) != len(expected) {
+		t.Fatalf("got %d actions, want %d", len(actions), len(expected))
+	}
+	for i, a := range actions {
+		if a != expected[i] {
+			t.Errorf("actions[%d] = %+v, want %+v", i, a, expected[i])
+		}
+	}
+
+	for _, spec := range []string{"edit,edit", "bad-name", "edit:TRACE"} {
+		if _, err := ParseScaffoldActions(spec); err == nil {
+			t.Errorf("ParseScaffoldActions(%q) expected error", spec)
+		}
+	}
+}
+
+func TestScaffold(t *testing.T) {
+	dir := t.TempDir()
+	registry := `package components
+
+import "github.com/pthm/hxcmp"
+
+// Init registers all components.
+func Init(store TodoStore, reg *hxcmp.Registry) {
+	reg.Add(
+		NewTodoList(store),
+		NewStats(store),
+	)
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "registry.go"), []byte(registry), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	g := New(Options{})
+	err := g.Scaffold(ScaffoldOptions{
+		Dir:       dir,
+		Name:      "task-detail",
+		Actions:   []ScaffoldAction{{Name: "edit", Method: "POST"}, {Name: "delete", Method: "DELETE"}},
+		Sensitive: true,
+	})
+	if err != nil {
+		t.Fatalf("Scaffold() error = %v", err)
+	}
+
+	src := readFile(t, filepath.Join(dir, "taskdetail.go"))
+	for _, want := range []string{
+		"package components",
+		"type TaskDetailProps struct",
+		`hxcmp.New[TaskDetailProps]("taskdetail")`,
+		"c.Sensitive()",
+		`c.Action("edit", c.handleEdit)` + "\n",
+		`c.Action("delete", c.handleDelete).Method(http.MethodDelete)`,
+		"func (c *TaskDetail) handleDelete(ctx context.Context, props TaskDetailProps, r *http.Request) hxcmp.Result[TaskDetailProps]",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("taskdetail.go missing %q:\n%s", want, src)
+		}
+	}
+
+	templ := readFile(t, filepath.Join(dir, "taskdetail.templ"))
+	if !strings.Contains(templ, "templ taskDetailTemplate(c *TaskDetail, props TaskDetailProps)") ||
+		!strings.Contains(templ, "c.WireDelete(props)...") {
+		t.Errorf("unexpected template:\n%s", templ)
+	}
+
+	test := readFile(t, filepath.Join(dir, "taskdetail_test.go"))
+	if !strings.Contains(test, "hxcmp.TestRender(comp, TaskDetailProps{") {
+		t.Errorf("unexpected test:\n%s", test)
+	}
+
+	reg := readFile(t, filepath.Join(dir, "registry.go"))
+	if !strings.Contains(reg, "\t\tNewStats(store),\n\t\tNewTaskDetail(),\n\t)") {
+		t.Errorf("component not registered:\n%s", reg)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "taskdetail_hx.go")); err != nil {
+		t.Errorf("generation did not run: %v", err)
+	}
+
+	// A second run must refuse to overwrite the component.
+	if err := g.Scaffold(ScaffoldOptions{Dir: dir, Name: "TaskDetail"}); err == nil {
+		t.Error("Scaffold() expected error for existing component")
+	}
+}
+
+func TestScaffoldCreatesRegistry(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "widgets")
+
+	g := New(Options{})
+	if err := g.Scaffold(ScaffoldOptions{Dir: dir, Name: "counter"}); err != nil {
+		t.Fatalf("Scaffold() error = %v", err)
+	}
+
+	reg := readFile(t, filepath.Join(dir, "registry.go"))
+	if !strings.Contains(reg, "package widgets") || !strings.Contains(reg, "reg.Add(NewCounter())") {
+		t.Errorf("unexpected registry:\n%s", reg)
+	}
+
+	src := readFile(t, filepath.Join(dir, "counter.go"))
+	if strings.Contains(src, "net/http") {
+		t.Errorf("component without actions should not import net/http:\n%s", src)
+	}
+}
+
+func TestScaffoldRegistryError(t *testing.T) {
+	dir := t.TempDir()
+	registry := `package components
+
+import "github.com/pthm/hxcmp"
+
+// Init registers components from a slice.
+func Init(reg *hxcmp.Registry) {
+	for _, c := range components {
+		register(reg, c)
+	}
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "registry.go"), []byte(registry), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	g := New(Options{})
+	opts := ScaffoldOptions{Dir: dir, Name: "counter"}
+	err := g.Scaffold(opts)
+	if err == nil || !strings.Contains(err.Error(), "register NewCounter manually") {
+		t.Fatalf("Scaffold() error = %v, want the registry error", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("files left behind: %v", entries)
+	}
+	if readFile(t, filepath.Join(dir, "registry.go")) != registry {
+		t.Error("registry.go modified")
+	}
+
+	// Once the registry is fixed, the retry doesn't find stale files
+	registry = strings.Replace(registry, "\tfor _, c := range components {\n\t\tregister(reg, c)\n\t}", "\treg.Add()", 1)
+	if err := os.WriteFile(filepath.Join(dir, "registry.go"), []byte(registry), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Scaffold(opts); err != nil {
+		t.Fatalf("Scaffold() retry error = %v", err)
+	}
+	if !strings.Contains(readFile(t, filepath.Join(dir, "registry.go")), "reg.Add(NewCounter())") {
+		t.Errorf("component not registered:\n%s", readFile(t, filepath.Join(dir, "registry.go")))
+	}
+}
+
+func TestInsertRegistrationSingleLine(t *testing.T) {
+	src := `package components
+
+import "github.com/pthm/hxcmp"
+
+func Init(reg *hxcmp.Registry) {
+	reg.Add(NewCounter())
+}
+`
+	out, err := insertRegistration([]byte(src), "NewWidget()")
+	if err != nil {
+		t.Fatalf("insertRegistration() error = %v", err)
+	}
+	if !strings.Contains(string(out), "reg.Add(NewCounter(), NewWidget())") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+func TestInsertRegistrationRegistryReceiver(t *testing.T) {
+	src := `package components
+
+import hx "github.com/pthm/hxcmp"
+
+func setup(reg *hx.Registry) {
+	reg.Add(NewOther())
+}
+
+func Init(r *hx.Registry, seen *Set) {
+	seen.Add("counter")
+	r.Add(NewCounter())
+}
+`
+	out, err := insertRegistration([]byte(src), "NewWidget()")
+	if err != nil {
+		t.Fatalf("insertRegistration() error = %v", err)
+	}
+	for _, want := range []string{"reg.Add(NewOther())", `seen.Add("counter")`, "r.Add(NewCounter(), NewWidget())"} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	// Other receivers' Add calls are never used
+	src = strings.Replace(src, "\tr.Add(NewCounter())\n", "", 1)
+	if _, err := insertRegistration([]byte(src), "NewWidget()"); err == nil ||
+		!strings.Contains(err.Error(), "no r.Add call found in Init") {
+		t.Errorf("without r.Add: error = %v", err)
+	}
+	src = strings.Replace(src, "func Init(r *hx.Registry, seen *Set)", "func Init(seen *Set)", 1)
+	if _, err := insertRegistration([]byte(src), "NewWidget()"); err == nil ||
+		!strings.Contains(err.Error(), "no Init(reg *hxcmp.Registry) function found") {
+		t.Errorf("without a registry parameter: error = %v", err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	return string(data)
+}
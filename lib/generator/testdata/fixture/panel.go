@@ -0,0 +1,100 @@
+package fixture
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/a-h/templ"
+	"github.com/pthm/hxcmp"
+)
+
+// PanelProps defines the props for the Panel component.
+type PanelProps struct {
+	Owner string `hx:"owner,required"`
+	Count int    `hx:"count,required,min=0,max=100"`
+	Mode  string `hx:"mode,default=view,oneof=view|edit"`
+}
+
+// PanelForm is the form input for the set action.
+type PanelForm struct {
+	Count int `form:"count"`
+}
+
+// panelStore is a dependency whose methods are registered as handlers.
+type panelStore struct {
+	step int
+}
+
+func (s *panelStore) Add(ctx context.Context, props PanelProps, r *http.Request) hxcmp.Result[PanelProps] {
+	props.Count += s.step
+	return hxcmp.OK(props)
+}
+
+// Broken has a signature dispatch doesn't support.
+func (s *panelStore) Broken(ctx context.Context, props PanelProps, step int) hxcmp.Result[PanelProps] {
+	return hxcmp.OK(props)
+}
+
+// Panel exercises handlers that are not methods of the component.
+type Panel struct {
+	*hxcmp.Component[PanelProps]
+	store *panelStore
+}
+
+// NewPanel creates a new Panel component.
+func NewPanel() *Panel {
+	c := &Panel{
+		Component: hxcmp.New[PanelProps]("panel"),
+		store:     &panelStore{step: 10},
+	}
+	c.Action("reset", func(ctx context.Context, props PanelProps) hxcmp.Result[PanelProps] {
+		props.Count = 0
+		return hxcmp.OK(props)
+	})
+	c.Action("set", func(ctx context.Context, props PanelProps, form PanelForm) hxcmp.Result[PanelProps] {
+		props.Count = form.Count
+		return hxcmp.OK(props)
+	})
+	c.Action("echo", echoPanel)
+	c.Action("mirror", func(ctx context.Context, props PanelProps) hxcmp.Result[PanelProps] {
+		mirrored := props
+		mirrored.Owner = "mirror"
+		return hxcmp.OK(props).OOB(c, mirrored).OOBTemplate("flash", templ.Raw("<em>mirrored</em>"))
+	})
+	c.Action("add", c.store.Add)
+	c.Action("broken", c.store.Broken)
+	c.Listens("widget:renamed")
+	return c
+}
+
+// echoPanel receives both the writer and the request.
+func echoPanel(ctx context.Context, props PanelProps, w http.ResponseWriter, r *http.Request) hxcmp.Result[PanelProps] {
+	w.Header().Set("X-Echo", r.FormValue("msg"))
+	props.Count++
+	return hxcmp.OK(props)
+}
+
+// Hydrate has nothing to load.
+func (c *Panel) Hydrate(ctx context.Context, props *PanelProps) error {
+	return nil
+}
+
+// panelRenders counts Panel renders, so tests can see when one is skipped.
+var panelRenders atomic.Int64
+
+// Version identifies the output of Render without rendering.
+func (c *Panel) Version(ctx context.Context, props PanelProps) (string, error) {
+	return fmt.Sprintf("%s/%d/%s", props.Owner, props.Count, props.Mode), nil
+}
+
+// Render produces the HTML output.
+func (c *Panel) Render(ctx context.Context, props PanelProps) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		panelRenders.Add(1)
+		_, err := fmt.Fprintf(w, `<div class="panel" data-mode="%s">%d</div>`, props.Mode, props.Count)
+		return err
+	})
+}
@@ -0,0 +1,183 @@
+package fixture
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/pthm/hxcmp"
+)
+
+func TestHandlerForms(t *testing.T) {
+	client := NewPanelTestClient(NewPanel())
+	props := PanelProps{Owner: "a", Count: 3}
+
+	if result := client.Reset(props); !result.HTMLContains(`>0<`) {
+		t.Errorf("Reset (func literal): status = %d, body = %s", result.StatusCode, result.HTML)
+	}
+
+	if result := client.Set(props, PanelForm{Count: 7}); !result.HTMLContains(`>7<`) {
+		t.Errorf("Set (func literal with form): status = %d, body = %s", result.StatusCode, result.HTML)
+	}
+
+	result := client.Echo(props, url.Values{"msg": {"hi"}})
+	if !result.HTMLContains(`>4<`) || result.Headers.Get("X-Echo") != "hi" {
+		t.Errorf("Echo (package func with w, r): headers = %v, body = %s", result.Headers, result.HTML)
+	}
+
+	if result := client.Add(props, nil); !result.HTMLContains(`>13<`) {
+		t.Errorf("Add (method value): status = %d, body = %s", result.StatusCode, result.HTML)
+	}
+
+	if result := client.Broken(props, nil); !result.HasStatus(http.StatusInternalServerError) {
+		t.Errorf("Broken: status = %d, want %d", result.StatusCode, http.StatusInternalServerError)
+	}
+}
+
+func TestPropsConstraints(t *testing.T) {
+	client := NewPanelTestClient(NewPanel())
+
+	if result := client.Render(PanelProps{Owner: "a", Count: 5}); !result.HTMLContains(`data-mode="view">5<`) {
+		t.Errorf("default: status = %d, body = %s", result.StatusCode, result.HTML)
+	}
+	// A required number is satisfied by an encoded zero
+	if result := client.Render(PanelProps{Owner: "a"}); !result.HTMLContains(`data-mode="view">0<`) {
+		t.Errorf("zero count: status = %d, body = %s", result.StatusCode, result.HTML)
+	}
+
+	for name, props := range map[string]PanelProps{
+		"missing required": {Count: 5},
+		"above max":        {Owner: "a", Count: 101},
+		"below min":        {Owner: "a", Count: -1},
+		"not in oneof":     {Owner: "a", Mode: "delete"},
+	} {
+		if result := client.Render(props); !result.HasStatus(http.StatusBadRequest) {
+			t.Errorf("%s: status = %d, want %d", name, result.StatusCode, http.StatusBadRequest)
+		}
+	}
+
+	// Without props, defaults apply and required fields are reported
+	reg := hxcmp.NewRegistry(hxcmp.TestKey())
+	var got error
+	reg.OnError = func(w http.ResponseWriter, r *http.Request, err error) {
+		got = err
+		w.WriteHeader(http.StatusBadRequest)
+	}
+	c := NewPanel()
+	reg.Add(c)
+	rec := httptest.NewRecorder()
+	reg.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, c.Prefix()+"/", nil))
+
+	var perr *hxcmp.PropsError
+	if !errors.As(got, &perr) || !hxcmp.IsInvalidProps(got) {
+		t.Fatalf("error = %v, want *hxcmp.PropsError", got)
+	}
+	if len(perr.Fields) != 2 || perr.Fields[0].Field != "owner" || perr.Fields[1].Field != "count" {
+		t.Errorf("Fields = %+v, want owner and count", perr.Fields)
+	}
+}
+
+func TestVersionETag(t *testing.T) {
+	reg := hxcmp.NewRegistry(hxcmp.TestKey())
+	c := NewPanel()
+	reg.Add(c)
+	url := c.URLRender(PanelProps{Owner: "a", Count: 5})
+
+	rec := httptest.NewRecorder()
+	reg.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+	etag := rec.Header().Get("ETag")
+	if rec.Code != http.StatusOK || etag == "" {
+		t.Fatalf("status = %d, ETag = %q", rec.Code, etag)
+	}
+	if got := rec.Header().Get("Vary"); got != "HX-Request, HX-Target" {
+		t.Errorf("Vary = %q", got)
+	}
+
+	renders := panelRenders.Load()
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	reg.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Errorf("conditional request: status = %d, body = %q", rec.Code, rec.Body.String())
+	}
+	if panelRenders.Load() != renders {
+		t.Error("Render was called for an unchanged version")
+	}
+
+	// Other props have another version
+	req = httptest.NewRequest(http.MethodGet, c.URLRender(PanelProps{Owner: "a", Count: 6}), nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	reg.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") == etag {
+		t.Errorf("changed version: status = %d, ETag = %q", rec.Code, rec.Header().Get("ETag"))
+	}
+}
+
+func TestRenderCache(t *testing.T) {
+	reg := hxcmp.NewRegistry(hxcmp.TestKey())
+	c, w := NewPanel(), NewWidget()
+	c.Cache(time.Minute, "widget-paged")
+	reg.Add(c, w)
+	props := PanelProps{Owner: "a", Count: 9}
+
+	render := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		reg.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, c.URLRender(props), nil))
+		return rec
+	}
+	first := render()
+	renders := panelRenders.Load()
+	second := render()
+	if second.Code != http.StatusOK || second.Body.String() != first.Body.String() {
+		t.Fatalf("cached response = %d %q, want %q", second.Code, second.Body.String(), first.Body.String())
+	}
+	if panelRenders.Load() != renders {
+		t.Error("Render was called for a cached render")
+	}
+
+	// The widget's next action triggers widget-paged
+	rec := httptest.NewRecorder()
+	reg.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, w.URLNext(WidgetProps{ID: "a"}), nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("next: status = %d", rec.Code)
+	}
+	render()
+	if panelRenders.Load() != renders+1 {
+		t.Error("Render was not called after the cache was invalidated")
+	}
+}
+
+func TestOOB(t *testing.T) {
+	reg := hxcmp.NewRegistry(hxcmp.TestKey())
+	c := NewPanel()
+	reg.Add(c)
+
+	rec := httptest.NewRecorder()
+	reg.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, c.URLMirror(PanelProps{Owner: "a", Count: 4}), nil))
+	want := `<div class="panel" data-mode="view">4</div>` +
+		`<div hx-swap-oob="true" class="panel" data-mode="view">4</div>` +
+		`<div id="flash" hx-swap-oob="innerHTML"><em>mirrored</em></div>`
+	if rec.Code != http.StatusOK || rec.Body.String() != want {
+		t.Errorf("response = %d %q, want %q", rec.Code, rec.Body.String(), want)
+	}
+
+	// Out-of-band components are authorized like their own renders
+	c.Require("view")
+	reg.Authorizer = func(ctx context.Context, req hxcmp.AuthRequest) error {
+		if req.Action == "" {
+			return hxcmp.ErrForbidden
+		}
+		return nil
+	}
+	rec = httptest.NewRecorder()
+	reg.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, c.URLMirror(PanelProps{Owner: "a"}), nil))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("denied out-of-band render: status = %d, body = %q", rec.Code, rec.Body.String())
+	}
+}
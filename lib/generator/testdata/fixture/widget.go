@@ -0,0 +1,102 @@
+package fixture
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/pthm/hxcmp"
+)
+
+// WidgetProps defines the props for the Widget component.
+type WidgetProps struct {
+	ID   string `hx:"id"`
+	Page int    `hx:"page,omitempty"`
+	// Hydrated data (not serialized)
+	Label string `hx:"-"`
+}
+
+// Tag is a named string type bound from a multi-value form field.
+type Tag string
+
+// WidgetForm is the form input for the update action.
+type WidgetForm struct {
+	Title    string
+	Page     int  `form:"page_number"`
+	Archived bool `form:"archived"`
+	Tags     []Tag
+	Due      time.Time `form:"due,layout=2006-01-02"`
+	Internal string    `form:"-"`
+}
+
+// WidgetRenamed is the payload of the widget:renamed event.
+type WidgetRenamed struct {
+	ID string `json:"id"`
+}
+
+// Widget exercises each supported handler signature.
+type Widget struct {
+	*hxcmp.Component[WidgetProps]
+}
+
+// NewWidget creates a new Widget component.
+func NewWidget() *Widget {
+	c := &Widget{
+		Component: hxcmp.New[WidgetProps]("widget"),
+	}
+	c.Action("next", c.handleNext)
+	c.Action("rename", c.handleRename)
+	c.Action("remove", c.handleRemove).Method(http.MethodDelete)
+	c.Action("preview", c.handlePreview).Method(http.MethodGet)
+	c.Action("update", c.handleUpdate)
+	c.Emits("widget:renamed", WidgetRenamed{})
+	return c
+}
+
+// Hydrate derives the label from the ID.
+func (c *Widget) Hydrate(ctx context.Context, props *WidgetProps) error {
+	if props.ID == "missing" {
+		return hxcmp.ErrNotFound
+	}
+	props.Label = "widget " + props.ID
+	return nil
+}
+
+// Render produces the HTML output.
+func (c *Widget) Render(ctx context.Context, props WidgetProps) templ.Component {
+	return widgetTemplate(c, props)
+}
+
+func (c *Widget) handleNext(ctx context.Context, props WidgetProps) hxcmp.Result[WidgetProps] {
+	props.Page++
+	return hxcmp.OK(props).Trigger("widget-paged")
+}
+
+func (c *Widget) handleRename(ctx context.Context, props WidgetProps, r *http.Request) hxcmp.Result[WidgetProps] {
+	props.ID = r.FormValue("id")
+	return EmitWidgetRenamed(hxcmp.OK(props), WidgetRenamed{ID: props.ID})
+}
+
+func (c *Widget) handleRemove(ctx context.Context, props WidgetProps, w http.ResponseWriter) hxcmp.Result[WidgetProps] {
+	w.WriteHeader(http.StatusNoContent)
+	return hxcmp.Skip[WidgetProps]()
+}
+
+func (c *Widget) handlePreview(ctx context.Context, props WidgetProps) hxcmp.Result[WidgetProps] {
+	return hxcmp.OK(props)
+}
+
+func (c *Widget) handleUpdate(ctx context.Context, props WidgetProps, form WidgetForm) hxcmp.Result[WidgetProps] {
+	if form.Archived {
+		return hxcmp.Skip[WidgetProps]()
+	}
+	tags := make([]string, len(form.Tags))
+	for i, t := range form.Tags {
+		tags[i] = string(t)
+	}
+	props.ID = form.Title + ":" + strings.Join(tags, ",") + ":" + form.Due.Format("Jan 2")
+	props.Page = form.Page
+	return hxcmp.OK(props)
+}
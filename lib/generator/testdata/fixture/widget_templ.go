@@ -0,0 +1,20 @@
+package fixture
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/a-h/templ"
+)
+
+// widgetTemplate stands in for the output of 'templ generate'.
+func widgetTemplate(c *Widget, props WidgetProps) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := fmt.Fprintf(w, `<div class="widget" data-id="%s" data-page="%d">%s</div>`, props.ID, props.Page, props.Label)
+		if props.Page < 0 {
+			return fmt.Errorf("page %d out of range", props.Page)
+		}
+		return err
+	})
+}
@@ -0,0 +1,538 @@
+package fixture
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pthm/hxcmp"
+)
+
+func newTestRegistry(t *testing.T) (*hxcmp.Registry, *Widget) {
+	t.Helper()
+	reg := hxcmp.NewRegistry(make([]byte, 32))
+	c := NewWidget()
+	reg.Add(c)
+	return reg, c
+}
+
+func serve(reg *hxcmp.Registry, req *http.Request) *httptest.ResponseRecorder {
+	req.Header.Set("HX-Request", "true")
+	rec := httptest.NewRecorder()
+	reg.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRender(t *testing.T) {
+	reg, c := newTestRegistry(t)
+
+	rec := serve(reg, httptest.NewRequest(http.MethodGet, c.URLRender(WidgetProps{ID: "a", Page: 2}), nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	if got := rec.Body.String(); got != `<div class="widget" data-id="a" data-page="2">widget a</div>` {
+		t.Errorf("body = %q", got)
+	}
+}
+
+func TestHydrateError(t *testing.T) {
+	reg, c := newTestRegistry(t)
+
+	rec := serve(reg, httptest.NewRequest(http.MethodGet, c.URLRender(WidgetProps{ID: "missing"}), nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestActionSignatures(t *testing.T) {
+	reg, c := newTestRegistry(t)
+	props := WidgetProps{ID: "a", Page: 1}
+
+	rec := serve(reg, httptest.NewRequest(http.MethodPost, c.URLNext(props), nil))
+	if !strings.Contains(rec.Body.String(), `data-page="2"`) || rec.Header().Get("HX-Trigger") != "widget-paged" {
+		t.Errorf("next: status = %d, headers = %v, body = %s", rec.Code, rec.Header(), rec.Body.String())
+	}
+
+	req := httptest.NewRequest(http.MethodPost, c.URLRename(props), strings.NewReader("id=b"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec = serve(reg, req)
+	if !strings.Contains(rec.Body.String(), `data-id="b"`) {
+		t.Errorf("rename: body = %s", rec.Body.String())
+	}
+	if got := rec.Header().Get("HX-Trigger"); got != `{"widget:renamed":{"id":"b"}}` {
+		t.Errorf("rename: HX-Trigger = %s", got)
+	}
+
+	rec = serve(reg, httptest.NewRequest(http.MethodDelete, c.URLRemove(props), nil))
+	if rec.Code != http.StatusNoContent || rec.Body.Len() != 0 {
+		t.Errorf("remove: status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+
+	rec = serve(reg, httptest.NewRequest(http.MethodPost, c.URLPreview(props), nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("preview with wrong method: status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestFormAction(t *testing.T) {
+	reg, c := newTestRegistry(t)
+	props := WidgetProps{ID: "a"}
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, c.URLUpdate(props), strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return serve(reg, req)
+	}
+
+	rec := post("title=hello&page_number=7&tags=x&tags=y&due=2024-03-05&internal=ignored")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("update: status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	if got := rec.Body.String(); !strings.Contains(got, `data-id="hello:x,y:Mar 5"`) || !strings.Contains(got, `data-page="7"`) {
+		t.Errorf("update: body = %s", got)
+	}
+
+	rec = post("title=hello&archived=on")
+	if rec.Code != http.StatusOK || rec.Body.Len() != 0 {
+		t.Errorf("archived: status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+
+	rec = post("page_number=seven&due=tomorrow")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid form: status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestWireAttributes(t *testing.T) {
+	_, c := newTestRegistry(t)
+	props := WidgetProps{ID: "a"}
+
+	attrs := c.WireNext(props)
+	if attrs["hx-post"] != c.Prefix()+"/next" || attrs["hx-vals"] == nil {
+		t.Errorf("WireNext = %v", attrs)
+	}
+	if attrs := c.WirePreview(props); !strings.HasPrefix(attrs["hx-get"].(string), c.Prefix()+"/preview?p=") {
+		t.Errorf("WirePreview = %v", attrs)
+	}
+}
+
+func TestURLBuilders(t *testing.T) {
+	_, c := newTestRegistry(t)
+	props := WidgetProps{ID: "a & b"}
+
+	u, err := url.Parse(c.URLNext(props))
+	if err != nil {
+		t.Fatalf("URLNext is not a valid URL: %v", err)
+	}
+	if u.Path != c.Prefix()+"/next" || u.Query().Get("p") == "" {
+		t.Errorf("URLNext = %q", u)
+	}
+
+	abs := c.AbsoluteURLRender("https://example.com/", props)
+	if !strings.HasPrefix(abs, "https://example.com"+c.Prefix()+"/?p=") {
+		t.Errorf("AbsoluteURLRender = %q", abs)
+	}
+}
+
+func TestGeneratedTestClient(t *testing.T) {
+	client := NewWidgetTestClient(NewWidget())
+	props := WidgetProps{ID: "a", Page: 1}
+
+	if result := client.Render(props); !result.IsOK() || !result.HTMLContains(`data-page="1"`) {
+		t.Errorf("Render: status = %d, body = %s", result.StatusCode, result.HTML)
+	}
+
+	if result := client.Next(props); !result.HTMLContains(`data-page="2"`) || !result.HasEvent("widget-paged") {
+		t.Errorf("Next: events = %v, body = %s", result.TriggeredEvents, result.HTML)
+	}
+
+	if result := client.Rename(props, url.Values{"id": {"b"}}); !result.HTMLContains(`data-id="b"`) {
+		t.Errorf("Rename: body = %s", result.HTML)
+	}
+
+	if result := client.Remove(props); !result.HasStatus(http.StatusNoContent) {
+		t.Errorf("Remove: status = %d", result.StatusCode)
+	}
+
+	if result := client.Preview(props); !result.IsOK() {
+		t.Errorf("Preview: status = %d", result.StatusCode)
+	}
+
+	form := WidgetForm{
+		Title: "hello",
+		Page:  7,
+		Tags:  []Tag{"x", "y"},
+		Due:   time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC),
+	}
+	if result := client.Update(props, form); !result.HTMLContainsAll(`data-id="hello:x,y:Mar 5"`, `data-page="7"`) {
+		t.Errorf("Update: status = %d, body = %s", result.StatusCode, result.HTML)
+	}
+
+	form.Archived = true
+	if result := client.Update(props, form); !result.IsOK() || result.HTML != "" {
+		t.Errorf("Update archived: status = %d, body = %s", result.StatusCode, result.HTML)
+	}
+}
+
+func TestExtensions(t *testing.T) {
+	c := NewWidget()
+
+	if got := c.TraceRemove(); got != "Widget.remove via DELETE" {
+		t.Errorf("TraceRemove() = %q", got)
+	}
+	if got := c.TraceNext(); got != "Widget.next via POST" {
+		t.Errorf("TraceNext() = %q", got)
+	}
+	if got := c.ActionCount(); got != 5 {
+		t.Errorf("ActionCount() = %d, want 5", got)
+	}
+}
+
+func TestGeneratedInfo(t *testing.T) {
+	info := NewWidget().HXGenerated()
+	if !strings.HasPrefix(info.SourceHash, "sha256:") {
+		t.Errorf("SourceHash = %q", info.SourceHash)
+	}
+
+	var actions []string
+	for _, a := range info.Actions {
+		actions = append(actions, a.Method+" "+a.Name)
+	}
+	if got := strings.Join(actions, ","); got != "POST next,GET preview,DELETE remove,POST rename,POST update" {
+		t.Errorf("Actions = %s", got)
+	}
+
+	// Generated code matches the constructor, so strict registration succeeds
+	reg := hxcmp.NewRegistry(make([]byte, 32))
+	reg.Strict = true
+	reg.Add(NewWidget())
+}
+
+func TestEvents(t *testing.T) {
+	if EventWidgetRenamed != "widget:renamed" {
+		t.Errorf("EventWidgetRenamed = %q", EventWidgetRenamed)
+	}
+	if got := ListenWidgetRenamed()["hx-trigger"]; got != "widget:renamed from:body" {
+		t.Errorf("ListenWidgetRenamed = %q", got)
+	}
+
+	reg, _ := newTestRegistry(t)
+	reg.Add(NewPanel())
+	for _, d := range reg.Components() {
+		switch d.Name {
+		case "widget":
+			if len(d.Emits) != 1 || d.Emits[0] != EventWidgetRenamed {
+				t.Errorf("widget emits %v", d.Emits)
+			}
+		case "panel":
+			if len(d.Listens) != 1 || d.Listens[0] != EventWidgetRenamed {
+				t.Errorf("panel listens %v", d.Listens)
+			}
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	reg := hxcmp.NewRegistry(make([]byte, 32))
+	c := NewWidget()
+	c.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Component", "widget")
+			next.ServeHTTP(w, r)
+		})
+	})
+	c.Action("next", c.handleNext).Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Action", "next")
+			next.ServeHTTP(w, r)
+		})
+	})
+	reg.Add(c)
+
+	props := WidgetProps{ID: "a"}
+	rec := serve(reg, httptest.NewRequest(http.MethodPost, c.URLNext(props), nil))
+	if rec.Header().Get("X-Component") != "widget" || rec.Header().Get("X-Action") != "next" || rec.Code != http.StatusOK {
+		t.Errorf("next: status = %d, headers = %v", rec.Code, rec.Header())
+	}
+
+	rec = serve(reg, httptest.NewRequest(http.MethodGet, c.URLRender(props), nil))
+	if rec.Header().Get("X-Component") != "widget" || rec.Header().Get("X-Action") != "" {
+		t.Errorf("render: headers = %v", rec.Header())
+	}
+}
+
+func TestAuthorization(t *testing.T) {
+	reg := hxcmp.NewRegistry(make([]byte, 32))
+	var checked []string
+	reg.Authorizer = func(ctx context.Context, req hxcmp.AuthRequest) error {
+		checked = append(checked, req.Action+":"+req.Policy)
+		// Props are hydrated before the check
+		if req.Policy == "widget:admin" && req.Props.(WidgetProps).Label != "widget admin" {
+			return hxcmp.ErrForbidden
+		}
+		return nil
+	}
+	c := NewWidget()
+	c.Require("widget:read")
+	c.Action("remove", c.handleRemove).Method(http.MethodDelete).Require("widget:admin").DisableWhenDenied()
+	reg.Add(c)
+
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		want   int
+		policy string
+	}{
+		{"render", http.MethodGet, c.URLRender(WidgetProps{ID: "a"}), http.StatusOK, ":widget:read"},
+		{"default policy", http.MethodPost, c.URLNext(WidgetProps{ID: "a"}), http.StatusOK, "next:widget:read"},
+		{"denied", http.MethodDelete, c.URLRemove(WidgetProps{ID: "a"}), http.StatusForbidden, "remove:widget:admin"},
+		{"allowed", http.MethodDelete, c.URLRemove(WidgetProps{ID: "admin"}), http.StatusNoContent, "remove:widget:admin"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checked = nil
+			rec := serve(reg, httptest.NewRequest(tt.method, tt.url, nil))
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if len(checked) != 1 || checked[0] != tt.policy {
+				t.Errorf("checked %v, want [%s]", checked, tt.policy)
+			}
+		})
+	}
+
+	ctx := context.Background()
+	if attrs := c.WireRemoveFor(ctx, WidgetProps{ID: "a"}); attrs["disabled"] != true || attrs["hx-delete"] != nil {
+		t.Errorf("WireRemoveFor(denied) = %v", attrs)
+	}
+	if attrs := c.WireRemoveFor(ctx, WidgetProps{ID: "a", Label: "widget admin"}); attrs["hx-delete"] != c.Prefix()+"/remove" {
+		t.Errorf("WireRemoveFor(allowed) = %v", attrs)
+	}
+
+	reg.Authorizer = nil
+	if rec := serve(reg, httptest.NewRequest(http.MethodGet, c.URLRender(WidgetProps{ID: "a"}), nil)); rec.Code != http.StatusForbidden {
+		t.Errorf("without Authorizer: status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	if attrs := c.WireNextFor(ctx, WidgetProps{ID: "a"}); len(attrs) != 0 {
+		t.Errorf("WireNextFor(without Authorizer) = %v", attrs)
+	}
+}
+
+func TestAuthorizationTestClient(t *testing.T) {
+	c := NewWidget()
+	c.Action("remove", c.handleRemove).Method(http.MethodDelete).Require("widget:admin")
+	client := NewWidgetTestClient(c)
+
+	if result := client.Remove(WidgetProps{ID: "admin"}); result.StatusCode != http.StatusForbidden {
+		t.Errorf("without Authorizer: status = %d, want %d", result.StatusCode, http.StatusForbidden)
+	}
+
+	client.SetAuthorizer(func(ctx context.Context, req hxcmp.AuthRequest) error {
+		if req.Props.(WidgetProps).Label != "widget admin" {
+			return hxcmp.ErrForbidden
+		}
+		return nil
+	})
+	if result := client.Remove(WidgetProps{ID: "admin"}); result.StatusCode != http.StatusNoContent {
+		t.Errorf("allowed: status = %d, want %d", result.StatusCode, http.StatusNoContent)
+	}
+	if result := client.Remove(WidgetProps{ID: "a"}); result.StatusCode != http.StatusForbidden {
+		t.Errorf("denied: status = %d, want %d", result.StatusCode, http.StatusForbidden)
+	}
+}
+
+func TestObserver(t *testing.T) {
+	spans := &hxcmp.SpanRecorder{}
+	reg := hxcmp.NewRegistry(make([]byte, 32))
+	reg.Observer = spans
+	c := NewWidget()
+	reg.Add(c)
+
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		action string
+		want   []hxcmp.Stage
+		failed hxcmp.Stage
+	}{
+		{"render", http.MethodGet, c.URLRender(WidgetProps{ID: "a"}), "",
+			[]hxcmp.Stage{hxcmp.StageDecode, hxcmp.StageHydrate, hxcmp.StageRender}, ""},
+		{"action", http.MethodPost, c.URLNext(WidgetProps{ID: "a"}), "next",
+			[]hxcmp.Stage{hxcmp.StageDecode, hxcmp.StageHydrate, hxcmp.StageHandler, hxcmp.StageRender}, ""},
+		{"skip", http.MethodDelete, c.URLRemove(WidgetProps{ID: "a"}), "remove",
+			[]hxcmp.Stage{hxcmp.StageDecode, hxcmp.StageHydrate, hxcmp.StageHandler}, ""},
+		{"hydrate error", http.MethodGet, c.URLRender(WidgetProps{ID: "missing"}), "",
+			[]hxcmp.Stage{hxcmp.StageDecode, hxcmp.StageHydrate}, hxcmp.StageHydrate},
+		{"decode error", http.MethodGet, c.Prefix() + "/?p=garbage", "",
+			[]hxcmp.Stage{hxcmp.StageDecode}, hxcmp.StageDecode},
+		{"unknown route", http.MethodGet, c.Prefix() + "/nope", "", []hxcmp.Stage{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spans.Reset()
+			serve(reg, httptest.NewRequest(tt.method, tt.url, nil))
+
+			if got := spans.Stages(); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("stages = %v, want %v", got, tt.want)
+			}
+			for _, s := range spans.Spans() {
+				if s.Prefix != c.Prefix() || s.Action != tt.action || s.Method != tt.method {
+					t.Errorf("span = %+v", s)
+				}
+				if failed := s.Err != nil; failed != (s.Stage == tt.failed) {
+					t.Errorf("%s span error = %v", s.Stage, s.Err)
+				}
+			}
+		})
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	reg, c := newTestRegistry(t)
+	serve(reg, httptest.NewRequest(http.MethodGet, c.URLRender(WidgetProps{ID: "a"}), nil))
+	serve(reg, httptest.NewRequest(http.MethodGet, c.URLRender(WidgetProps{ID: "missing"}), nil))
+	serve(reg, httptest.NewRequest(http.MethodPost, c.URLNext(WidgetProps{ID: "a"}), nil))
+	serve(reg, httptest.NewRequest(http.MethodPost, c.URLUpdate(WidgetProps{ID: "a"})+"&page_number=x", nil))
+
+	byAction := make(map[string]hxcmp.ActionMetrics)
+	for _, m := range reg.Metrics() {
+		byAction[m.Action] = m
+	}
+	if len(byAction) != 6 {
+		t.Errorf("Metrics() has %d routes, want 6", len(byAction))
+	}
+
+	render := byAction[""]
+	if render.Requests != 2 || render.Hydrate.Count != 2 || render.Render.Count != 1 || render.Errors[hxcmp.CategoryNotFound] != 1 {
+		t.Errorf("render metrics = %+v", render)
+	}
+	if next := byAction["next"]; next.Requests != 1 || next.Render.Count != 1 || len(next.Errors) != 0 {
+		t.Errorf("next metrics = %+v", next)
+	}
+	if update := byAction["update"]; update.Requests != 1 || update.Errors[hxcmp.CategoryValidation] != 1 {
+		t.Errorf("update metrics = %+v", update)
+	}
+}
+
+func TestLogging(t *testing.T) {
+	var buf bytes.Buffer
+	reg := hxcmp.NewRegistry(make([]byte, 32))
+	reg.Logger = slog.New(slog.NewJSONHandler(&buf, nil))
+	c := NewWidget()
+	reg.Add(c)
+
+	serve(reg, httptest.NewRequest(http.MethodPost, c.URLNext(WidgetProps{ID: "a", Page: 2}), nil))
+
+	var rec map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatalf("log = %q: %v", buf.String(), err)
+	}
+	props, _ := rec["props"].(map[string]any)
+	if rec["action"] != "next" || rec["status"] != float64(http.StatusOK) || props["id"] != "a" || props["page"] != float64(2) {
+		t.Errorf("record = %v", rec)
+	}
+}
+
+func TestRenderBuffered(t *testing.T) {
+	reg, c := newTestRegistry(t)
+
+	rec := serve(reg, httptest.NewRequest(http.MethodGet, c.URLRender(WidgetProps{ID: "a"}), nil))
+	if got, want := rec.Header().Get("Content-Length"), strconv.Itoa(rec.Body.Len()); got != want {
+		t.Errorf("Content-Length = %q, want %q", got, want)
+	}
+
+	// The template fails after writing its markup for a negative page
+	var renderErr error
+	reg, c = hxcmp.NewRegistry(make([]byte, 32)), NewWidget()
+	reg.OnError = func(w http.ResponseWriter, r *http.Request, err error) {
+		renderErr = err
+		http.Error(w, "render failed", http.StatusInternalServerError)
+	}
+	reg.Add(c)
+	rec = serve(reg, httptest.NewRequest(http.MethodGet, c.URLRender(WidgetProps{ID: "a", Page: -1}), nil))
+	if rec.Code != http.StatusInternalServerError || strings.Contains(rec.Body.String(), "widget") {
+		t.Errorf("failed render: status = %d, body = %q", rec.Code, rec.Body.String())
+	}
+	if !hxcmp.IsRenderError(renderErr) {
+		t.Errorf("OnError got %v, want a render error", renderErr)
+	}
+
+	c.Streaming()
+	rec = serve(reg, httptest.NewRequest(http.MethodGet, c.URLRender(WidgetProps{ID: "a", Page: -1}), nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `data-page="-1"`) {
+		t.Errorf("streamed render: status = %d, body = %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestETags(t *testing.T) {
+	reg, c := newTestRegistry(t)
+	url := c.URLRender(WidgetProps{ID: "a"})
+
+	rec := serve(reg, httptest.NewRequest(http.MethodGet, url, nil))
+	if rec.Header().Get("ETag") != "" {
+		t.Fatalf("ETag = %q without ETags()", rec.Header().Get("ETag"))
+	}
+
+	c.ETags()
+	rec = serve(reg, httptest.NewRequest(http.MethodGet, url, nil))
+	etag := rec.Header().Get("ETag")
+	if etag != hxcmp.ETag(rec.Body.Bytes()) {
+		t.Fatalf("ETag = %q, want the hash of %q", etag, rec.Body.String())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = serve(reg, req)
+	if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Errorf("conditional request: status = %d, body = %q", rec.Code, rec.Body.String())
+	}
+
+	// Action responses are not conditional
+	req = httptest.NewRequest(http.MethodPost, c.URLNext(WidgetProps{ID: "a"}), nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = serve(reg, req)
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") != "" {
+		t.Errorf("action: status = %d, ETag = %q", rec.Code, rec.Header().Get("ETag"))
+	}
+}
+
+// guestKey marks a request context as a guest's.
+type guestKey struct{}
+
+func TestRenderCacheAuthorization(t *testing.T) {
+	reg, c := newTestRegistry(t)
+	c.Cache(time.Minute).Require("widget:read")
+	var labels []string
+	reg.Authorizer = func(ctx context.Context, req hxcmp.AuthRequest) error {
+		label := req.Props.(WidgetProps).Label
+		labels = append(labels, label)
+		if ctx.Value(guestKey{}) != nil && label == "widget private" {
+			return hxcmp.ErrForbidden
+		}
+		return nil
+	}
+	url := c.URLRender(WidgetProps{ID: "private"})
+
+	if rec := serve(reg, httptest.NewRequest(http.MethodGet, url, nil)); rec.Code != http.StatusOK {
+		t.Fatalf("owner: status = %d", rec.Code)
+	}
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	req = req.WithContext(context.WithValue(req.Context(), guestKey{}, true))
+	if rec := serve(reg, req); rec.Code != http.StatusForbidden {
+		t.Errorf("guest on a cached render: status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	if len(labels) != 2 || labels[0] != "widget private" || labels[1] != "widget private" {
+		t.Errorf("policies saw labels %q, want hydrated props", labels)
+	}
+}
@@ -0,0 +1,205 @@
+package hxcmp
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// Redacted replaces the props of sensitive components in log records.
+const Redacted = "[redacted]"
+
+// requestLogKey is the context key of a request's *requestLog.
+type requestLogKey struct{}
+
+// requestLog is the logging state of a component request, shared through
+// its context by the registry, generated dispatch and Logger.
+type requestLog struct {
+	logger       *slog.Logger // With component, prefix, action and method
+	props        slog.Value   // Set by LogProps; zero until props are decoded
+	hasProps     bool
+	err          error // First error the request failed with
+	renderFailed bool  // Rendering failed, buffered or streaming
+
+	// The failed render was buffered: nothing reached the client and
+	// OnError sent the response. Otherwise a streaming render was cut
+	// short and the client got a truncated fragment.
+	renderBuffered bool
+}
+
+// Logger returns the logger for a component request, pre-populated with
+// the component name and prefix, the action, the method and, once decoded,
+// the props. Use it in Hydrate and handlers:
+//
+//	func (c *Cart) handleCheckout(ctx context.Context, props Props) hxcmp.Result[Props] {
+//	    hxcmp.Logger(ctx).Info("checking out", "items", len(props.Items))
+//	    ...
+//	}
+//
+// It is based on Registry.Logger, or slog.Default if that is nil. Outside a
+// component request it returns slog.Default.
+func Logger(ctx context.Context) *slog.Logger {
+	rl, ok := ctx.Value(requestLogKey{}).(*requestLog)
+	if !ok {
+		return slog.Default()
+	}
+	if rl.hasProps {
+		return rl.logger.With(slog.Attr{Key: "props", Value: rl.props})
+	}
+	return rl.logger
+}
+
+// LogProps records decoded props on the request's logger. Generated
+// dispatch calls it after decoding.
+//
+// Props of sensitive components are logged as Redacted. Props types can
+// implement slog.LogValuer to choose what is logged; otherwise the encoded
+// props are logged, as they appear, signed, in URLs.
+func (c *Component[P]) LogProps(ctx context.Context, props P) {
+	rl, ok := ctx.Value(requestLogKey{}).(*requestLog)
+	if !ok {
+		return
+	}
+	rl.props = c.logValue(props)
+	rl.hasProps = true
+}
+
+// logValue returns the log value of props, redacted for sensitive
+// components.
+func (c *Component[P]) logValue(props P) slog.Value {
+	if c.sensitive {
+		return slog.StringValue(Redacted)
+	}
+	if _, ok := any(props).(slog.LogValuer); ok {
+		return slog.AnyValue(props).Resolve()
+	}
+	if e, ok := any(props).(Encodable); ok {
+		m := e.HXEncode()
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		attrs := make([]slog.Attr, len(keys))
+		for i, k := range keys {
+			attrs[i] = slog.Any(k, m[k])
+		}
+		return slog.GroupValue(attrs...)
+	}
+	return slog.AnyValue(props)
+}
+
+// recordError notes the error a request failed with.
+func recordError(ctx context.Context, err error) {
+	if rl, ok := ctx.Value(requestLogKey{}).(*requestLog); ok && rl.err == nil {
+		rl.err = err
+	}
+}
+
+// recordRenderError notes that rendering failed. buffered reports whether
+// the output was buffered, so that the error goes on to OnError; streaming
+// failures happen after the response started and are only logged.
+func recordRenderError(ctx context.Context, err error, buffered bool) {
+	if rl, ok := ctx.Value(requestLogKey{}).(*requestLog); ok {
+		rl.renderFailed = true
+		rl.renderBuffered = buffered
+		if rl.err == nil {
+			rl.err = err
+		}
+	}
+}
+
+// statusWriter records the status code written to a response.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher when the underlying writer does.
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		if w.status == 0 {
+			w.status = http.StatusOK
+		}
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying writer for http.ResponseController.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// logRequests wraps a component's handler so that Logger works in its
+// requests and each request is logged to Registry.Logger when it finishes.
+// Without a Logger, only requests whose rendering failed are logged, to
+// slog.Default.
+func (reg *Registry) logRequests(comp any, h http.Handler) http.Handler {
+	ar, ok := comp.(actionRouter)
+	if !ok {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		base := reg.Logger
+		if base == nil {
+			base = slog.Default()
+		}
+		rl := &requestLog{logger: base.With(
+			slog.String("component", ar.Name()),
+			slog.String("prefix", ar.Prefix()),
+			slog.String("action", ar.actionFor(r)),
+			slog.String("method", r.Method),
+		)}
+		ctx := context.WithValue(r.Context(), requestLogKey{}, rl)
+		sw := &statusWriter{ResponseWriter: w}
+		start := time.Now()
+		h.ServeHTTP(sw, r.WithContext(ctx))
+
+		if reg.Logger == nil && !rl.renderFailed {
+			return
+		}
+		status := sw.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		level := slog.LevelInfo
+		switch {
+		case status >= 500 || rl.renderFailed && !rl.renderBuffered:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+		attrs := []slog.Attr{
+			slog.Int("status", status),
+			slog.Duration("duration", time.Since(start)),
+		}
+		if rl.err != nil {
+			attrs = append(attrs,
+				slog.String("error", rl.err.Error()),
+				slog.String("error_category", ErrorCategory(rl.err)),
+			)
+		}
+		msg := "hxcmp: request"
+		if rl.renderFailed {
+			msg = "hxcmp: render error"
+			attrs = append(attrs, slog.Bool("truncated", !rl.renderBuffered))
+		}
+		Logger(ctx).LogAttrs(ctx, level, msg, attrs...)
+	})
+}
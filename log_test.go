@@ -0,0 +1,179 @@
+package hxcmp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// logRecords decodes the JSON records written to buf.
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+		records = append(records, rec)
+	}
+	return records
+}
+
+func TestRequestLogging(t *testing.T) {
+	var buf bytes.Buffer
+	reg := NewRegistry(make([]byte, 32))
+	reg.Logger = slog.New(slog.NewJSONHandler(&buf, nil))
+	c := newWidget("widget")
+	reg.Add(c)
+
+	tests := []struct {
+		name     string
+		method   string
+		action   string
+		id       string
+		level    string
+		status   float64
+		category string
+	}{
+		{"render", http.MethodGet, "", "1", "INFO", 200, ""},
+		{"not found", http.MethodGet, "", "missing", "WARN", 404, CategoryNotFound},
+		{"render error", http.MethodGet, "", "unrenderable", "ERROR", 500, CategoryRender},
+		{"panic", http.MethodGet, "", "panic", "ERROR", 500, CategoryPanic},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			req := httptest.NewRequest(tt.method, widgetURL(t, reg, c, tt.action, widgetProps{ID: tt.id}), nil)
+			reg.Handler().ServeHTTP(httptest.NewRecorder(), req)
+
+			records := logRecords(t, &buf)
+			rec := records[len(records)-1]
+			if rec["level"] != tt.level || rec["status"] != tt.status || rec["component"] != "widget" ||
+				rec["prefix"] != c.Prefix() || rec["action"] != tt.action || rec["method"] != tt.method {
+				t.Errorf("record = %v", rec)
+			}
+			if _, ok := rec["duration"]; !ok {
+				t.Errorf("record has no duration: %v", rec)
+			}
+			if got, _ := rec["error_category"].(string); got != tt.category {
+				t.Errorf("error_category = %q, want %q", got, tt.category)
+			}
+			if props, _ := rec["props"].(map[string]any); props["id"] != tt.id {
+				t.Errorf("props = %v, want id %q", rec["props"], tt.id)
+			}
+		})
+	}
+}
+
+func TestLoggerInHandler(t *testing.T) {
+	var buf bytes.Buffer
+	reg := NewRegistry(make([]byte, 32))
+	reg.Logger = slog.New(slog.NewJSONHandler(&buf, nil))
+	c := newWidget("widget")
+	c.Sensitive()
+	reg.Add(c)
+
+	req := httptest.NewRequest(http.MethodPost, widgetURL(t, reg, c, "save", widgetProps{ID: "secret"}), nil)
+	reg.Handler().ServeHTTP(httptest.NewRecorder(), req)
+
+	records := logRecords(t, &buf)
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want the handler's and the request's", len(records))
+	}
+	rec := records[0]
+	if rec["msg"] != "saving widget" || rec["action"] != "save" || rec["component"] != "widget" || rec["props"] != Redacted {
+		t.Errorf("handler record = %v", rec)
+	}
+	if strings.Contains(buf.String(), "secret") {
+		t.Errorf("sensitive props were logged:\n%s", buf.String())
+	}
+}
+
+func TestRequestLoggingWithoutLogger(t *testing.T) {
+	var buf bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+
+	reg := NewRegistry(make([]byte, 32))
+	c := newWidget("widget")
+	reg.Add(c)
+
+	for _, id := range []string{"1", "missing", "unrenderable"} {
+		req := httptest.NewRequest(http.MethodGet, widgetURL(t, reg, c, "", widgetProps{ID: id}), nil)
+		reg.Handler().ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	records := logRecords(t, &buf)
+	if len(records) != 1 || records[0]["msg"] != "hxcmp: render error" || records[0]["error"] != "hxcmp: render failed: template exploded" {
+		t.Errorf("records = %v, want only the render error", records)
+	}
+}
+
+func TestRenderErrorLogging(t *testing.T) {
+	tests := []struct {
+		name      string
+		streaming bool
+		status    float64
+		truncated bool
+	}{
+		// OnError responded; the client saw a clean error
+		{"buffered", false, 500, false},
+		// The fragment was cut short after a 200
+		{"streaming", true, 200, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			reg := NewRegistry(make([]byte, 32))
+			reg.Logger = slog.New(slog.NewJSONHandler(&buf, nil))
+			c := newWidget("widget")
+			if tt.streaming {
+				c.Streaming()
+			}
+			reg.Add(c)
+
+			req := httptest.NewRequest(http.MethodGet, widgetURL(t, reg, c, "", widgetProps{ID: "unrenderable"}), nil)
+			reg.Handler().ServeHTTP(httptest.NewRecorder(), req)
+
+			records := logRecords(t, &buf)
+			rec := records[len(records)-1]
+			if rec["msg"] != "hxcmp: render error" || rec["level"] != "ERROR" || rec["status"] != tt.status ||
+				rec["truncated"] != tt.truncated || rec["error_category"] != CategoryRender {
+				t.Errorf("record = %v", rec)
+			}
+		})
+	}
+}
+
+func TestLoggerOutsideRequest(t *testing.T) {
+	if Logger(context.Background()) != slog.Default() {
+		t.Error("Logger() outside a request is not slog.Default()")
+	}
+}
+
+// loggedProps implements slog.LogValuer to choose what is logged.
+type loggedProps struct {
+	ID    string
+	Token string
+}
+
+func (p loggedProps) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("id", p.ID))
+}
+
+func TestLogValue(t *testing.T) {
+	c := New[loggedProps]("logged")
+	got := c.logValue(loggedProps{ID: "1", Token: "hunter2"})
+	if got.Kind() != slog.KindGroup || got.String() != "[id=1]" {
+		t.Errorf("logValue() = %v", got)
+	}
+}
@@ -0,0 +1,368 @@
+package hxcmp
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Error categories counted by the registry's metrics. Errors are placed in
+// the first category whose sentinel they match, in this order; other errors
+// are counted as CategoryHandler.
+const (
+	CategoryPanic      = "panic"      // IsPanic
+	CategoryForbidden  = "forbidden"  // IsForbidden
+	CategoryNotFound   = "not_found"  // IsNotFound
+	CategoryDecryption = "decryption" // IsDecryptionError
+	CategoryValidation = "validation" // IsValidationError
+	CategoryHydration  = "hydration"  // IsHydrationError
+	CategoryRender     = "render"     // IsRenderError
+	CategoryHandler    = "handler"    // Any other error
+)
+
+// ErrorCategory returns the metrics category of err, or "" for nil.
+func ErrorCategory(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case IsPanic(err):
+		return CategoryPanic
+	case IsForbidden(err):
+		return CategoryForbidden
+	case IsNotFound(err):
+		return CategoryNotFound
+	case IsDecryptionError(err):
+		return CategoryDecryption
+	case IsValidationError(err):
+		return CategoryValidation
+	case IsHydrationError(err):
+		return CategoryHydration
+	case IsRenderError(err):
+		return CategoryRender
+	default:
+		return CategoryHandler
+	}
+}
+
+// DefaultBuckets are the upper bounds of the registry's latency histograms.
+var DefaultBuckets = []time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+}
+
+// ActionMetrics holds the metrics of one route: a component's render
+// endpoint, when Action is empty, or one of its actions.
+type ActionMetrics struct {
+	Component string            `json:"component"` // Component name
+	Prefix    string            `json:"prefix"`
+	Action    string            `json:"action,omitempty"`
+	Requests  uint64            `json:"requests"`
+	Errors    map[string]uint64 `json:"errors,omitempty"` // By ErrorCategory
+	Hydrate   Histogram         `json:"hydrate"`
+	Render    Histogram         `json:"render"`
+}
+
+// Histogram is a snapshot of a latency histogram.
+type Histogram struct {
+	Count   uint64        `json:"count"`
+	Sum     time.Duration `json:"sum"`
+	Buckets []Bucket      `json:"buckets"` // Cumulative, by increasing UpperBound
+}
+
+// Bucket counts the observations at or below UpperBound.
+type Bucket struct {
+	UpperBound time.Duration `json:"upper_bound"`
+	Count      uint64        `json:"count"`
+}
+
+// Mean returns the average observed duration, or 0 if there are none.
+func (h Histogram) Mean() time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+	return h.Sum / time.Duration(h.Count)
+}
+
+// metricsKey identifies a route in metrics.
+type metricsKey struct {
+	prefix string
+	action string
+}
+
+// routeMetrics accumulates the metrics of one route.
+type routeMetrics struct {
+	requests uint64
+	errors   map[string]uint64
+	hydrate  histogram
+	render   histogram
+}
+
+// histogram accumulates durations into DefaultBuckets.
+type histogram struct {
+	count  uint64
+	sum    time.Duration
+	counts []uint64 // Per bucket, not cumulative
+}
+
+func (h *histogram) observe(d time.Duration) {
+	if h.counts == nil {
+		h.counts = make([]uint64, len(DefaultBuckets))
+	}
+	h.count++
+	h.sum += d
+	for i, bound := range DefaultBuckets {
+		if d <= bound {
+			h.counts[i]++
+			break
+		}
+	}
+}
+
+func (h *histogram) snapshot() Histogram {
+	s := Histogram{Count: h.count, Sum: h.sum, Buckets: make([]Bucket, len(DefaultBuckets))}
+	var cumulative uint64
+	for i, bound := range DefaultBuckets {
+		if h.counts != nil {
+			cumulative += h.counts[i]
+		}
+		s.Buckets[i] = Bucket{UpperBound: bound, Count: cumulative}
+	}
+	return s
+}
+
+// metrics collects the registry's per-route metrics. It observes generated
+// dispatch for requests and durations, and counts errors as they are passed
+// to OnError.
+type metrics struct {
+	mu     sync.Mutex
+	routes map[metricsKey]*routeMetrics
+}
+
+func newMetrics() *metrics {
+	return &metrics{routes: make(map[metricsKey]*routeMetrics)}
+}
+
+// route returns the metrics for a route, creating them if needed. The
+// caller must hold m.mu.
+func (m *metrics) route(prefix, action string) *routeMetrics {
+	key := metricsKey{prefix, action}
+	rm, ok := m.routes[key]
+	if !ok {
+		rm = &routeMetrics{errors: make(map[string]uint64)}
+		m.routes[key] = rm
+	}
+	return rm
+}
+
+// register creates zeroed metrics for a component's routes, so they are
+// reported before the first request.
+func (m *metrics) register(prefix string, actions []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.route(prefix, "")
+	for _, action := range actions {
+		m.route(prefix, action)
+	}
+}
+
+// recordError counts err against a route.
+func (m *metrics) recordError(prefix, action string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.route(prefix, action).errors[ErrorCategory(err)]++
+}
+
+// OnDecode counts a request; every routed request is decoded exactly once.
+func (m *metrics) OnDecode(ctx context.Context, span Span) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.route(span.Prefix, span.Action).requests++
+}
+
+// OnHydrate records the hydrate duration.
+func (m *metrics) OnHydrate(ctx context.Context, span Span) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.route(span.Prefix, span.Action).hydrate.observe(span.Duration)
+}
+
+// OnHandler is a no-op; handler errors are counted when they reach OnError.
+func (m *metrics) OnHandler(ctx context.Context, span Span) {}
+
+// OnRender records the render duration.
+func (m *metrics) OnRender(ctx context.Context, span Span) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.route(span.Prefix, span.Action).render.observe(span.Duration)
+}
+
+// snapshot copies the metrics, sorted by prefix and action. names maps
+// prefixes to component names.
+func (m *metrics) snapshot(names map[string]string) []ActionMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out := make([]ActionMetrics, 0, len(m.routes))
+	for key, rm := range m.routes {
+		am := ActionMetrics{
+			Component: names[key.prefix],
+			Prefix:    key.prefix,
+			Action:    key.action,
+			Requests:  rm.requests,
+			Hydrate:   rm.hydrate.snapshot(),
+			Render:    rm.render.snapshot(),
+		}
+		if len(rm.errors) > 0 {
+			am.Errors = make(map[string]uint64, len(rm.errors))
+			for category, n := range rm.errors {
+				am.Errors[category] = n
+			}
+		}
+		out = append(out, am)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Prefix != out[j].Prefix {
+			return out[i].Prefix < out[j].Prefix
+		}
+		return out[i].Action < out[j].Action
+	})
+	return out
+}
+
+// multiObserver passes each span to several observers in order.
+type multiObserver []Observer
+
+func (o multiObserver) OnDecode(ctx context.Context, span Span) {
+	for _, obs := range o {
+		obs.OnDecode(ctx, span)
+	}
+}
+
+func (o multiObserver) OnHydrate(ctx context.Context, span Span) {
+	for _, obs := range o {
+		obs.OnHydrate(ctx, span)
+	}
+}
+
+func (o multiObserver) OnHandler(ctx context.Context, span Span) {
+	for _, obs := range o {
+		obs.OnHandler(ctx, span)
+	}
+}
+
+func (o multiObserver) OnRender(ctx context.Context, span Span) {
+	for _, obs := range o {
+		obs.OnRender(ctx, span)
+	}
+}
+
+// Metrics returns a snapshot of the registry's metrics, one entry per
+// component route, sorted by prefix and action:
+//
+//	for _, m := range reg.Metrics() {
+//	    log.Printf("%s %s: %d requests, %d not found, render %v avg",
+//	        m.Component, m.Action, m.Requests, m.Errors[hxcmp.CategoryNotFound], m.Render.Mean())
+//	}
+//
+// Metrics are collected from generated dispatch; components served without
+// generated code are not measured.
+func (reg *Registry) Metrics() []ActionMetrics {
+	reg.mu.RLock()
+	names := make(map[string]string, len(reg.components))
+	for prefix, comp := range reg.components {
+		if ar, ok := comp.(actionRouter); ok {
+			names[prefix] = ar.Name()
+		}
+	}
+	reg.mu.RUnlock()
+
+	return reg.metrics.snapshot(names)
+}
+
+// MetricsHandler returns an HTTP handler that serves the registry's metrics
+// in the Prometheus text exposition format:
+//
+//	mux.Handle("/metrics/hxcmp", reg.MetricsHandler())
+//
+// It exports hxcmp_requests_total, hxcmp_errors_total (labelled by
+// category), and the hxcmp_hydrate_duration_seconds and
+// hxcmp_render_duration_seconds histograms, each labelled with component,
+// prefix and action. Like DescribeHandler, it is not mounted automatically.
+func (reg *Registry) MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		bw := bufio.NewWriter(w)
+		writePrometheus(bw, reg.Metrics())
+		bw.Flush()
+	})
+}
+
+// writePrometheus writes metrics in the Prometheus text exposition format.
+func writePrometheus(w *bufio.Writer, ms []ActionMetrics) {
+	fmt.Fprintln(w, "# HELP hxcmp_requests_total Component requests served.")
+	fmt.Fprintln(w, "# TYPE hxcmp_requests_total counter")
+	for _, m := range ms {
+		fmt.Fprintf(w, "hxcmp_requests_total{%s} %d\n", promLabels(m), m.Requests)
+	}
+
+	fmt.Fprintln(w, "# HELP hxcmp_errors_total Component request errors by category.")
+	fmt.Fprintln(w, "# TYPE hxcmp_errors_total counter")
+	for _, m := range ms {
+		categories := make([]string, 0, len(m.Errors))
+		for category := range m.Errors {
+			categories = append(categories, category)
+		}
+		sort.Strings(categories)
+		for _, category := range categories {
+			fmt.Fprintf(w, "hxcmp_errors_total{%s,category=%s} %d\n", promLabels(m), promQuote(category), m.Errors[category])
+		}
+	}
+
+	writePromHistogram(w, "hxcmp_hydrate_duration_seconds", "Time spent in Hydrate.", ms, func(m ActionMetrics) Histogram { return m.Hydrate })
+	writePromHistogram(w, "hxcmp_render_duration_seconds", "Time spent rendering.", ms, func(m ActionMetrics) Histogram { return m.Render })
+}
+
+func writePromHistogram(w *bufio.Writer, name, help string, ms []ActionMetrics, get func(ActionMetrics) Histogram) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s histogram\n", name)
+	for _, m := range ms {
+		h := get(m)
+		labels := promLabels(m)
+		for _, b := range h.Buckets {
+			fmt.Fprintf(w, "%s_bucket{%s,le=\"%s\"} %d\n", name, labels, promFloat(b.UpperBound.Seconds()), b.Count)
+		}
+		fmt.Fprintf(w, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, labels, h.Count)
+		fmt.Fprintf(w, "%s_sum{%s} %s\n", name, labels, promFloat(h.Sum.Seconds()))
+		fmt.Fprintf(w, "%s_count{%s} %d\n", name, labels, h.Count)
+	}
+}
+
+// promLabels returns the component, prefix and action labels of m.
+func promLabels(m ActionMetrics) string {
+	return "component=" + promQuote(m.Component) + ",prefix=" + promQuote(m.Prefix) + ",action=" + promQuote(m.Action)
+}
+
+// promQuote quotes a label value, escaping backslashes, quotes and newlines.
+func promQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + r.Replace(s) + `"`
+}
+
+func promFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,182 @@
+package hxcmp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a-h/templ"
+)
+
+func TestErrorCategory(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{&PanicError{Value: "boom"}, CategoryPanic},
+		{fmt.Errorf("%w: admin only", ErrForbidden), CategoryForbidden},
+		{WrapHydrateError(ErrNotFound), CategoryNotFound},
+		{ErrSignatureInvalid, CategoryDecryption},
+		{&ValidationError{Fields: []FieldError{{Field: "age"}}}, CategoryValidation},
+		{WrapHydrateError(errors.New("db down")), CategoryHydration},
+		{WrapRenderError(errors.New("bad template")), CategoryRender},
+		{errors.New("db down"), CategoryHandler},
+	}
+	for _, tt := range tests {
+		if got := ErrorCategory(tt.err); got != tt.want {
+			t.Errorf("ErrorCategory(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	reg := NewRegistry(make([]byte, 32))
+	c := newWidget("widget")
+	c.Action("remove", c.handleSave).Method(http.MethodDelete).Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		})
+	})
+	reg.Add(c)
+
+	serve := func(method, action string, props widgetProps) {
+		reg.Handler().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, widgetURL(t, reg, c, action, props), nil))
+	}
+	serve(http.MethodGet, "", widgetProps{ID: "missing"})
+	serve(http.MethodGet, "", widgetProps{ID: "missing"})
+	serve(http.MethodDelete, "remove", widgetProps{ID: "1"})
+
+	// The hand-written widget doesn't observe its stages; report them as
+	// generated dispatch would
+	r := httptest.NewRequest(http.MethodPost, c.Prefix()+"/save", nil)
+	c.Observe(r, StageDecode, "save", time.Now(), nil)
+	c.Observe(r, StageHydrate, "save", time.Now().Add(-3*time.Millisecond), nil)
+	c.Observe(r, StageHandler, "save", time.Now(), nil)
+	c.Observe(r, StageRender, "save", time.Now(), nil)
+
+	ms := reg.Metrics()
+	if len(ms) != 3 {
+		t.Fatalf("Metrics() returned %d routes, want 3: %+v", len(ms), ms)
+	}
+	byAction := make(map[string]ActionMetrics)
+	for _, m := range ms {
+		if m.Component != "widget" || m.Prefix != c.Prefix() {
+			t.Errorf("route labels = %q %q", m.Component, m.Prefix)
+		}
+		byAction[m.Action] = m
+	}
+
+	if got := byAction[""].Errors[CategoryNotFound]; got != 2 {
+		t.Errorf("render not_found errors = %d, want 2", got)
+	}
+	if got := byAction["remove"].Errors[CategoryPanic]; got != 1 {
+		t.Errorf("remove panic errors = %d, want 1", got)
+	}
+	save := byAction["save"]
+	if save.Requests != 1 || save.Hydrate.Count != 1 || save.Render.Count != 1 || len(save.Errors) != 0 {
+		t.Errorf("save metrics = %+v", save)
+	}
+	if save.Hydrate.Mean() < 3*time.Millisecond {
+		t.Errorf("hydrate mean = %v, want at least 3ms", save.Hydrate.Mean())
+	}
+	// 3ms falls in the 5ms bucket, which is cumulative from there on
+	for _, b := range save.Hydrate.Buckets {
+		want := uint64(0)
+		if b.UpperBound >= 5*time.Millisecond {
+			want = 1
+		}
+		if b.Count != want {
+			t.Errorf("hydrate bucket %v = %d, want %d", b.UpperBound, b.Count, want)
+		}
+	}
+}
+
+// reflectWidget has no generated dispatch, so the registry serves it by
+// reflection.
+type reflectWidget struct {
+	*Component[widgetProps]
+}
+
+func (c *reflectWidget) Hydrate(ctx context.Context, props *widgetProps) error {
+	if props.ID == "missing" {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (c *reflectWidget) Render(ctx context.Context, props widgetProps) templ.Component {
+	return templ.Raw(`<div class="widget">` + props.ID + `</div>`)
+}
+
+func TestMetricsReflection(t *testing.T) {
+	reg := NewRegistry(make([]byte, 32))
+	c := &reflectWidget{Component: New[widgetProps]("reflected")}
+	c.Action("fail", func(ctx context.Context, props widgetProps) Result[widgetProps] {
+		return Err(props, errors.New("db down"))
+	})
+	reg.Add(c)
+
+	serve := func(method, action string, props widgetProps) {
+		encoded, err := reg.Encoder().Encode(props, false)
+		if err != nil {
+			t.Fatalf("Encode() error = %v", err)
+		}
+		reg.Handler().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, c.Prefix()+"/"+action+"?p="+encoded, nil))
+	}
+	serve(http.MethodGet, "", widgetProps{ID: "missing"})
+	serve(http.MethodPost, "fail", widgetProps{ID: "1"})
+
+	byAction := make(map[string]ActionMetrics)
+	for _, m := range reg.Metrics() {
+		byAction[m.Action] = m
+	}
+	if got := byAction[""].Errors[CategoryNotFound]; got != 1 {
+		t.Errorf("render not_found errors = %d, want 1", got)
+	}
+	if got := byAction["fail"].Errors[CategoryHandler]; got != 1 {
+		t.Errorf("fail handler errors = %d, want 1", got)
+	}
+}
+
+func TestMetricsHandler(t *testing.T) {
+	reg := NewRegistry(make([]byte, 32))
+	c := newWidget("widget")
+	reg.Add(c)
+	reg.Handler().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, widgetURL(t, reg, c, "", widgetProps{ID: "missing"}), nil))
+	c.Observe(httptest.NewRequest(http.MethodGet, c.Prefix()+"/", nil), StageDecode, "", time.Now(), nil)
+
+	rec := httptest.NewRecorder()
+	reg.MetricsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	labels := fmt.Sprintf(`component="widget",prefix=%q,action=""`, c.Prefix())
+	body := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE hxcmp_requests_total counter\n",
+		"hxcmp_requests_total{" + labels + "} 1\n",
+		`hxcmp_requests_total{component="widget",prefix="` + c.Prefix() + `",action="save"} 0` + "\n",
+		"hxcmp_errors_total{" + labels + `,category="not_found"} 1` + "\n",
+		"# TYPE hxcmp_render_duration_seconds histogram\n",
+		"hxcmp_hydrate_duration_seconds_bucket{" + labels + `,le="0.005"} 0` + "\n",
+		"hxcmp_hydrate_duration_seconds_bucket{" + labels + `,le="+Inf"} 0` + "\n",
+		"hxcmp_render_duration_seconds_count{" + labels + "} 0\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestPromQuote(t *testing.T) {
+	if got, want := promQuote("a\"b\\c\nd"), `"a\"b\\c\nd"`; got != want {
+		t.Errorf("promQuote() = %s, want %s", got, want)
+	}
+}
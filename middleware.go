@@ -0,0 +1,66 @@
+package hxcmp
+
+import "net/http"
+
+// Use adds middleware that wraps every request the component serves: the
+// render endpoint and all actions. Middleware runs in the order given,
+// before props are decoded:
+//
+//	c := &Reports{Component: hxcmp.New[Props]("reports")}
+//	c.Use(requireFeature("reports"), auditLog)
+//
+// Middleware is applied when the component is registered, so call Use in
+// the constructor, before Registry.Add.
+func (c *Component[P]) Use(mw ...func(http.Handler) http.Handler) *Component[P] {
+	c.middleware = append(c.middleware, mw...)
+	return c
+}
+
+// Use adds middleware that wraps requests to this action only. It runs
+// inside the component's middleware:
+//
+//	c.Action("export", c.handleExport).Use(timeout(30 * time.Second))
+//
+// Like Component.Use, it must be called before the component is registered.
+func (ab *ActionBuilder) Use(mw ...func(http.Handler) http.Handler) *ActionBuilder {
+	ab.action.middleware = append(ab.action.middleware, mw...)
+	return ab
+}
+
+// middlewareProvider is implemented by Component[P] and promoted onto every
+// component that embeds it, giving the registry the middleware to apply.
+type middlewareProvider interface {
+	wrapHandler(h http.Handler) http.Handler
+}
+
+// wrapHandler wraps h, the component's dispatch, with its action and
+// component middleware.
+func (c *Component[P]) wrapHandler(h http.Handler) http.Handler {
+	actions := make(map[string]http.Handler)
+	for name, a := range c.actions {
+		if len(a.middleware) > 0 {
+			actions[name] = chain(h, a.middleware)
+		}
+	}
+
+	if len(actions) > 0 {
+		next := h
+		h = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ah, ok := actions[c.actionFor(r)]; ok {
+				ah.ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	return chain(h, c.middleware)
+}
+
+// chain wraps h with middleware so that the first runs outermost.
+func chain(h http.Handler, mw []func(http.Handler) http.Handler) http.Handler {
+	for i := len(mw) - 1; i >= 0; i-- {
+		h = mw[i](h)
+	}
+	return h
+}
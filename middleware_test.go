@@ -0,0 +1,74 @@
+package hxcmp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// tagMiddleware appends name to the X-Chain response header, recording the
+// order middleware ran in.
+func tagMiddleware(name string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("X-Chain", name)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	reg := NewRegistry(make([]byte, 32))
+	c := &widget{Component: New[widgetProps]("widget")}
+	c.Use(tagMiddleware("outer"), tagMiddleware("inner"))
+	c.Action("save", c.handleSave).Use(tagMiddleware("save"))
+	c.Action("remove", c.handleSave).Method(http.MethodDelete)
+	reg.Add(c)
+
+	tests := []struct {
+		name   string
+		method string
+		action string
+		want   string
+	}{
+		{"render", http.MethodGet, "", "outer,inner"},
+		{"action with middleware", http.MethodPost, "save", "outer,inner,save"},
+		{"action without middleware", http.MethodDelete, "remove", "outer,inner"},
+		{"wrong method", http.MethodGet, "save", "outer,inner"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, widgetURL(t, reg, c, tt.action, widgetProps{ID: "1"}), nil)
+			reg.Handler().ServeHTTP(rec, req)
+
+			if got := strings.Join(rec.Header().Values("X-Chain"), ","); got != tt.want {
+				t.Errorf("middleware = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	reg := NewRegistry(make([]byte, 32))
+	c := newWidget("widget")
+	c.Action("save", c.handleSave).Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "disabled", http.StatusServiceUnavailable)
+		})
+	})
+	reg.Add(c)
+
+	rec := httptest.NewRecorder()
+	reg.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, widgetURL(t, reg, c, "save", widgetProps{ID: "1"}), nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("gated action: status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+
+	rec = httptest.NewRecorder()
+	reg.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, widgetURL(t, reg, c, "", widgetProps{ID: "1"}), nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("render: status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
@@ -0,0 +1,83 @@
+package hxcmp
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Stage is a step of the component request lifecycle.
+type Stage string
+
+// Lifecycle stages reported to an Observer, in the order they run.
+const (
+	StageDecode  Stage = "decode"  // Props decoding and constraint checks
+	StageHydrate Stage = "hydrate" // The component's Hydrate
+	StageHandler Stage = "handler" // The action handler
+	StageRender  Stage = "render"  // Rendering the component's templ output
+)
+
+// Span describes one lifecycle stage of a component request.
+type Span struct {
+	Stage    Stage
+	Prefix   string        // Component URL prefix
+	Action   string        // Action name; empty for the render endpoint
+	Method   string        // HTTP method
+	Start    time.Time     // When the stage started
+	Duration time.Duration // How long the stage took
+	Err      error         // Error the stage returned, if any
+}
+
+// Observer receives a Span as each lifecycle stage of a component request
+// finishes. Use it to feed tracing or timing systems; ctx is the request's
+// context, so an Observer can attach spans to the trace it carries. In
+// tests, SpanRecorder records the spans instead.
+//
+// Stages that don't run, such as the handler for the render endpoint or
+// Hydrate after props fail to decode, are not reported. Callbacks run on
+// the request goroutine, so they should be fast.
+type Observer interface {
+	OnDecode(ctx context.Context, span Span)
+	OnHydrate(ctx context.Context, span Span)
+	OnHandler(ctx context.Context, span Span)
+	OnRender(ctx context.Context, span Span)
+}
+
+// SetObserver is called by the registry during component registration to
+// install its Observer.
+//
+// User code should not call this directly.
+func (c *Component[P]) SetObserver(observer Observer) {
+	c.observer = observer
+}
+
+// Observe reports a lifecycle stage that started at start to the registry's
+// Observer. Generated dispatch calls it after each stage.
+func (c *Component[P]) Observe(r *http.Request, stage Stage, action string, start time.Time, err error) {
+	if stage == StageRender && err != nil {
+		recordRenderError(r.Context(), err, !c.streaming)
+	}
+	if c.observer == nil {
+		return
+	}
+	span := Span{
+		Stage:    stage,
+		Prefix:   c.prefix,
+		Action:   action,
+		Method:   r.Method,
+		Start:    start,
+		Duration: time.Since(start),
+		Err:      err,
+	}
+	ctx := r.Context()
+	switch stage {
+	case StageDecode:
+		c.observer.OnDecode(ctx, span)
+	case StageHydrate:
+		c.observer.OnHydrate(ctx, span)
+	case StageHandler:
+		c.observer.OnHandler(ctx, span)
+	case StageRender:
+		c.observer.OnRender(ctx, span)
+	}
+}
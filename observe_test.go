@@ -0,0 +1,54 @@
+package hxcmp
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestObserve(t *testing.T) {
+	spans := &SpanRecorder{}
+	reg := NewRegistry(make([]byte, 32))
+	reg.Observer = spans
+	c := newWidget("widget")
+	reg.Add(c)
+
+	errBoom := errors.New("boom")
+	r := httptest.NewRequest(http.MethodPost, c.Prefix()+"/save", nil)
+	start := time.Now().Add(-time.Second)
+	for _, stage := range []Stage{StageDecode, StageHydrate, StageHandler, StageRender} {
+		var err error
+		if stage == StageHandler {
+			err = errBoom
+		}
+		c.Observe(r, stage, "save", start, err)
+	}
+
+	if got, want := spans.Stages(), []Stage{StageDecode, StageHydrate, StageHandler, StageRender}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Stages() = %v, want %v", got, want)
+	}
+	s := spans.Spans()[2]
+	if s.Prefix != c.Prefix() || s.Action != "save" || s.Method != http.MethodPost || s.Err != errBoom {
+		t.Errorf("handler span = %+v", s)
+	}
+	if !s.Start.Equal(start) || s.Duration < time.Second {
+		t.Errorf("span timing: start %v, duration %v", s.Start, s.Duration)
+	}
+
+	spans.Reset()
+	if got := spans.Spans(); len(got) != 0 {
+		t.Errorf("Spans() after Reset = %v", got)
+	}
+}
+
+func TestObserveWithoutObserver(t *testing.T) {
+	reg := NewRegistry(make([]byte, 32))
+	c := newWidget("widget")
+	reg.Add(c)
+
+	// Must not panic
+	c.Observe(httptest.NewRequest(http.MethodGet, c.Prefix()+"/", nil), StageRender, "", time.Now(), nil)
+}
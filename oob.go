@@ -0,0 +1,186 @@
+package hxcmp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html"
+	"io"
+
+	"github.com/a-h/templ"
+)
+
+// OOBSwap is an out-of-band swap added to a Result with OOB or OOBTemplate.
+type OOBSwap struct {
+	component OOBComponent // Set by OOB
+	props     any
+	id        string // Set by OOBTemplate
+	tmpl      templ.Component
+}
+
+// OOBProps converts the props passed to Result.OOB for the component to P.
+// Generated HXRender calls it.
+func (c *Component[P]) OOBProps(props any) (P, error) {
+	switch p := props.(type) {
+	case P:
+		return p, nil
+	case *P:
+		if p != nil {
+			return *p, nil
+		}
+	}
+	var zero P
+	return zero, fmt.Errorf("hxcmp: out-of-band props for %s are %T, want %T", c.name, props, zero)
+}
+
+// WithOOB returns tmpl followed by the out-of-band swaps, for one response.
+// Generated dispatch calls it with Result.GetOOB when rendering an action's
+// result.
+//
+// Components are hydrated, and their policies checked, before WithOOB
+// returns; the first error fails the response. Their Render output must have
+// a single root element with the id of the element it replaces, which is
+// marked with hx-swap-oob="true" unless it sets hx-swap-oob itself, such as
+// hx-swap-oob="outerHTML:#other".
+func (c *Component[P]) WithOOB(ctx context.Context, tmpl templ.Component, swaps []OOBSwap) (templ.Component, error) {
+	if len(swaps) == 0 {
+		return tmpl, nil
+	}
+	parts := make([]templ.Component, len(swaps))
+	for i, s := range swaps {
+		if s.component == nil {
+			parts[i] = oobTemplate(s.id, s.tmpl)
+			continue
+		}
+		rendered, err := s.component.HXRender(ctx, s.props)
+		if err != nil {
+			return nil, err
+		}
+		parts[i] = oobComponent(s.component, rendered)
+	}
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		if err := tmpl.Render(ctx, w); err != nil {
+			return err
+		}
+		for _, part := range parts {
+			if err := part.Render(ctx, w); err != nil {
+				return err
+			}
+		}
+		return nil
+	}), nil
+}
+
+// oobComponent renders tmpl, the output of comp, with hx-swap-oob added to
+// its root element.
+func oobComponent(comp OOBComponent, tmpl templ.Component) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		var buf bytes.Buffer
+		if err := tmpl.Render(ctx, &buf); err != nil {
+			return err
+		}
+		marked, ok := markOOB(buf.Bytes())
+		if !ok {
+			return fmt.Errorf("hxcmp: out-of-band render of %T has no root element", comp)
+		}
+		_, err := w.Write(marked)
+		return err
+	})
+}
+
+// oobTemplate renders tmpl as the new content of the element with id.
+func oobTemplate(id string, tmpl templ.Component) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		if _, err := io.WriteString(w, `<div id="`+html.EscapeString(id)+`" hx-swap-oob="innerHTML">`); err != nil {
+			return err
+		}
+		if err := tmpl.Render(ctx, w); err != nil {
+			return err
+		}
+		_, err := io.WriteString(w, `</div>`)
+		return err
+	})
+}
+
+// markOOB adds hx-swap-oob="true" to the first element of fragment, after
+// any leading whitespace and comments, and reports whether there was one.
+// An element that already has hx-swap-oob is left as it is.
+func markOOB(fragment []byte) ([]byte, bool) {
+	rest := bytes.TrimLeft(fragment, " \t\r\n")
+	for bytes.HasPrefix(rest, []byte("<!--")) {
+		end := bytes.Index(rest, []byte("-->"))
+		if end < 0 {
+			return nil, false
+		}
+		rest = bytes.TrimLeft(rest[end+3:], " \t\r\n")
+	}
+	if len(rest) < 2 || rest[0] != '<' || !isASCIILetter(rest[1]) {
+		return nil, false
+	}
+	name := 1 + bytes.IndexFunc(rest[1:], func(r rune) bool {
+		return r == ' ' || r == '\t' || r == '\r' || r == '\n' || r == '/' || r == '>'
+	})
+	if name == 0 {
+		return nil, false
+	}
+	if hasOOBAttr(rest[name:]) {
+		return fragment, true
+	}
+	start := len(fragment) - len(rest)
+	var out bytes.Buffer
+	out.Grow(len(fragment) + 20)
+	out.Write(fragment[:start+name])
+	out.WriteString(` hx-swap-oob="true"`)
+	out.Write(fragment[start+name:])
+	return out.Bytes(), true
+}
+
+// hasOOBAttr reports whether the attributes of a start tag, up to its
+// closing '>', include hx-swap-oob or data-hx-swap-oob.
+func hasOOBAttr(attrs []byte) bool {
+	i := 0
+	for i < len(attrs) && attrs[i] != '>' {
+		if isHTMLSpace(attrs[i]) || attrs[i] == '/' {
+			i++
+			continue
+		}
+		start := i
+		for i < len(attrs) && !isHTMLSpace(attrs[i]) && attrs[i] != '/' && attrs[i] != '>' && attrs[i] != '=' {
+			i++
+		}
+		name := bytes.ToLower(attrs[start:i])
+		if string(name) == "hx-swap-oob" || string(name) == "data-hx-swap-oob" {
+			return true
+		}
+		for i < len(attrs) && isHTMLSpace(attrs[i]) {
+			i++
+		}
+		if i == len(attrs) || attrs[i] != '=' {
+			continue
+		}
+		i++
+		for i < len(attrs) && isHTMLSpace(attrs[i]) {
+			i++
+		}
+		if i < len(attrs) && (attrs[i] == '"' || attrs[i] == '\'') {
+			end := bytes.IndexByte(attrs[i+1:], attrs[i])
+			if end < 0 {
+				return false
+			}
+			i += end + 2
+			continue
+		}
+		for i < len(attrs) && !isHTMLSpace(attrs[i]) && attrs[i] != '>' {
+			i++
+		}
+	}
+	return false
+}
+
+func isHTMLSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\r' || b == '\n' || b == '\f'
+}
+
+func isASCIILetter(b byte) bool {
+	return 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z'
+}
@@ -0,0 +1,99 @@
+package hxcmp
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+// oobStub is an OOBComponent that renders html, or fails with err.
+type oobStub struct {
+	html string
+	err  error
+}
+
+func (s oobStub) HXRender(ctx context.Context, props any) (templ.Component, error) {
+	return templ.Raw(s.html), s.err
+}
+
+func TestMarkOOB(t *testing.T) {
+	tests := []struct {
+		fragment string
+		want     string
+		ok       bool
+	}{
+		{`<div id="a">x</div>`, `<div hx-swap-oob="true" id="a">x</div>`, true},
+		{"\n  <li>x</li>", "\n  <li hx-swap-oob=\"true\">x</li>", true},
+		{`<!-- a --><p id="a"/>`, `<!-- a --><p hx-swap-oob="true" id="a"/>`, true},
+		{`<hr>`, `<hr hx-swap-oob="true">`, true},
+		// An existing hx-swap-oob chooses the swap and is kept
+		{`<div id="a" hx-swap-oob="outerHTML:#b">x</div>`, `<div id="a" hx-swap-oob="outerHTML:#b">x</div>`, true},
+		{`<tr HX-SWAP-OOB='beforeend:#rows' id=a>`, `<tr HX-SWAP-OOB='beforeend:#rows' id=a>`, true},
+		{`<li data-hx-swap-oob id="a"/>`, `<li data-hx-swap-oob id="a"/>`, true},
+		// Only the root's own attributes count
+		{`<div title="hx-swap-oob" data-x=hx-swap-oob><p hx-swap-oob="true"></p></div>`,
+			`<div hx-swap-oob="true" title="hx-swap-oob" data-x=hx-swap-oob><p hx-swap-oob="true"></p></div>`, true},
+		{`text`, ``, false},
+		{`<!-- unterminated`, ``, false},
+		{``, ``, false},
+	}
+	for _, tt := range tests {
+		got, ok := markOOB([]byte(tt.fragment))
+		if ok != tt.ok || string(got) != tt.want {
+			t.Errorf("markOOB(%q) = %q, %v, want %q, %v", tt.fragment, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestOOBProps(t *testing.T) {
+	c := New[widgetProps]("widget")
+	for _, props := range []any{widgetProps{ID: "a"}, &widgetProps{ID: "a"}} {
+		if p, err := c.OOBProps(props); err != nil || p.ID != "a" {
+			t.Errorf("OOBProps(%T) = %+v, %v", props, p, err)
+		}
+	}
+	if _, err := c.OOBProps("a"); err == nil || !strings.Contains(err.Error(), "are string") {
+		t.Errorf("OOBProps(string) error = %v", err)
+	}
+}
+
+func TestWithOOB(t *testing.T) {
+	c := New[widgetProps]("widget")
+	main := templ.Raw("<p>main</p>")
+
+	if tmpl, err := c.WithOOB(context.Background(), main, nil); err != nil || tmpl == nil {
+		t.Errorf("WithOOB() without swaps = %v, %v", tmpl, err)
+	}
+
+	swaps := OK(widgetProps{}).
+		OOB(oobStub{html: `<nav id="sidebar">3</nav>`}, nil).
+		OOBTemplate(`a"b`, templ.Raw("<b>hi</b>")).
+		GetOOB()
+	tmpl, err := c.WithOOB(context.Background(), main, swaps)
+	if err != nil {
+		t.Fatalf("WithOOB() error = %v", err)
+	}
+	var b strings.Builder
+	if err := tmpl.Render(context.Background(), &b); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	want := `<p>main</p><nav hx-swap-oob="true" id="sidebar">3</nav><div id="a&#34;b" hx-swap-oob="innerHTML"><b>hi</b></div>`
+	if b.String() != want {
+		t.Errorf("output = %q, want %q", b.String(), want)
+	}
+
+	errNoSidebar := errors.New("no sidebar")
+	swaps = OK(widgetProps{}).OOB(oobStub{err: errNoSidebar}, nil).GetOOB()
+	if _, err := c.WithOOB(context.Background(), main, swaps); !errors.Is(err, errNoSidebar) {
+		t.Errorf("WithOOB() error = %v, want %v", err, errNoSidebar)
+	}
+
+	swaps = OK(widgetProps{}).OOB(oobStub{html: "just text"}, nil).GetOOB()
+	tmpl, _ = c.WithOOB(context.Background(), main, swaps)
+	if err := tmpl.Render(context.Background(), &b); err == nil {
+		t.Error("Render() of an out-of-band fragment without a root element succeeded")
+	}
+}
@@ -0,0 +1,81 @@
+package hxcmp
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"strings"
+)
+
+// actionRouter is implemented by Component[P] and promoted onto every
+// component that embeds it, letting the registry name the action a request
+// is for.
+type actionRouter interface {
+	Name() string
+	Prefix() string
+	actionFor(r *http.Request) string
+}
+
+// actionFor returns the action a request is routed to, the way generated
+// HXServeHTTP routes it, or "" for the render endpoint and unknown routes.
+func (c *Component[P]) actionFor(r *http.Request) string {
+	path := strings.TrimPrefix(r.URL.Path, c.prefix+"/")
+	a, ok := c.actions[path]
+	if !ok {
+		return ""
+	}
+	method := a.method
+	if method == "" {
+		method = http.MethodPost
+	}
+	if r.Method != method {
+		return ""
+	}
+	return a.name
+}
+
+// recoverPanics wraps a component's handler so that a panic anywhere in its
+// middleware or dispatch is passed to OnError as a *PanicError instead of
+// dropping the connection. http.ErrAbortHandler is re-panicked, since it is
+// how handlers deliberately abort a response.
+//
+// A panic after the response has started can't be turned into an error
+// response, since the status and part of the body are already sent. It is
+// logged and counted, and the response is aborted with http.ErrAbortHandler
+// so the client sees a failed request instead of a truncated fragment.
+func (reg *Registry) recoverPanics(comp any, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sw := &statusWriter{ResponseWriter: w}
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+
+			perr := &PanicError{Value: v, Stack: debug.Stack(), Component: fmt.Sprintf("%T", comp)}
+			if ar, ok := comp.(actionRouter); ok {
+				perr.Component = ar.Name()
+				perr.Action = ar.actionFor(r)
+			}
+			if sw.status != 0 {
+				if ar, ok := comp.(actionRouter); ok {
+					reg.metrics.recordError(ar.Prefix(), perr.Action, perr)
+				}
+				ctx := r.Context()
+				Logger(ctx).LogAttrs(ctx, slog.LevelError, "hxcmp: panic after response started",
+					slog.Int("status", sw.status),
+					slog.String("error", perr.Error()),
+					slog.String("error_category", ErrorCategory(perr)),
+					slog.String("stack", string(perr.Stack)),
+				)
+				panic(http.ErrAbortHandler)
+			}
+			reg.errorHandler(comp, reg.OnError)(w, r, perr)
+		}()
+		h.ServeHTTP(sw, r)
+	})
+}
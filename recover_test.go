@@ -0,0 +1,149 @@
+package hxcmp
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRecoverPanics(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	reg := NewRegistry(make([]byte, 32))
+	var got error
+	reg.OnError = func(w http.ResponseWriter, r *http.Request, err error) {
+		got = err
+		http.Error(w, "oops", http.StatusInternalServerError)
+	}
+	c := newWidget("widget")
+	c.Action("save", c.handleSave).Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic(errBoom)
+		})
+	})
+	reg.Add(c)
+
+	tests := []struct {
+		name   string
+		method string
+		action string
+		id     string
+		want   PanicError
+		wraps  error
+	}{
+		{"hydrate", http.MethodGet, "", "panic", PanicError{Value: "hydrate exploded", Component: "widget"}, nil},
+		{"action", http.MethodPost, "save", "1", PanicError{Value: errBoom, Component: "widget", Action: "save"}, errBoom},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, widgetURL(t, reg, c, tt.action, widgetProps{ID: tt.id}), nil)
+			reg.Handler().ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusInternalServerError {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+			}
+			if !IsPanic(got) {
+				t.Fatalf("OnError got %v, want a panic error", got)
+			}
+			var perr *PanicError
+			errors.As(got, &perr)
+			if perr.Value != tt.want.Value || perr.Component != tt.want.Component || perr.Action != tt.want.Action {
+				t.Errorf("PanicError = %+v, want %+v", perr, tt.want)
+			}
+			if !strings.Contains(string(perr.Stack), "recover_test.go") && !strings.Contains(string(perr.Stack), "registry_test.go") {
+				t.Errorf("stack does not include the panic site:\n%s", perr.Stack)
+			}
+			if tt.wraps != nil && !errors.Is(got, tt.wraps) {
+				t.Errorf("errors.Is(%v, %v) = false", got, tt.wraps)
+			}
+		})
+	}
+}
+
+func TestRecoverPanicsAbortHandler(t *testing.T) {
+	reg := NewRegistry(make([]byte, 32))
+	c := newWidget("widget")
+	c.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic(http.ErrAbortHandler)
+		})
+	})
+	reg.Add(c)
+
+	defer func() {
+		if v := recover(); v != http.ErrAbortHandler {
+			t.Errorf("recovered %v, want http.ErrAbortHandler", v)
+		}
+	}()
+	reg.Handler().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, c.Prefix()+"/", nil))
+	t.Error("ServeHTTP returned, want http.ErrAbortHandler to propagate")
+}
+
+func TestRecoverPanicsAfterWrite(t *testing.T) {
+	var buf bytes.Buffer
+	reg := NewRegistry(make([]byte, 32))
+	reg.Logger = slog.New(slog.NewJSONHandler(&buf, nil))
+	called := false
+	reg.OnError = func(w http.ResponseWriter, r *http.Request, err error) {
+		called = true
+	}
+	c := newWidget("widget")
+	c.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, "<div>partial")
+			panic("late")
+		})
+	})
+	reg.Add(c)
+
+	rec := httptest.NewRecorder()
+	func() {
+		defer func() {
+			if v := recover(); v != http.ErrAbortHandler {
+				t.Errorf("recovered %v, want http.ErrAbortHandler", v)
+			}
+		}()
+		reg.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, widgetURL(t, reg, c, "", widgetProps{ID: "1"}), nil))
+		t.Error("ServeHTTP returned, want the response aborted")
+	}()
+
+	if called {
+		t.Error("OnError called after the response started")
+	}
+	if rec.Body.String() != "<div>partial" {
+		t.Errorf("body = %q, want only the partial output", rec.Body.String())
+	}
+	records := logRecords(t, &buf)
+	if len(records) != 1 {
+		t.Fatalf("got %d records, want 1:\n%s", len(records), buf.String())
+	}
+	r := records[0]
+	if r["msg"] != "hxcmp: panic after response started" || r["level"] != "ERROR" || r["status"] != float64(200) ||
+		r["error_category"] != CategoryPanic || r["component"] != "widget" ||
+		!strings.Contains(r["stack"].(string), "recover_test.go") {
+		t.Errorf("record = %v", r)
+	}
+	var panics uint64
+	for _, am := range reg.Metrics() {
+		if am.Component == "widget" && am.Action == "" {
+			panics = am.Errors[CategoryPanic]
+		}
+	}
+	if panics != 1 {
+		t.Errorf("panic count = %d, want 1", panics)
+	}
+}
+
+func TestPanicErrorMessage(t *testing.T) {
+	err := &PanicError{Value: "nil map", Component: "cart", Action: "add"}
+	if got, want := err.Error(), "hxcmp: panic in cart.add: nil map"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
@@ -3,8 +3,10 @@ package hxcmp
 import (
 	"context"
 	"crypto/rand"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"reflect"
 	"strings"
@@ -101,6 +103,8 @@ type Registry struct {
 	mux        *http.ServeMux
 	encoder    *Encoder
 	components map[string]any // map[prefix]component
+	metrics    *metrics
+	cache      *RenderCache
 
 	// OnError is called when a component returns an error or encounters
 	// hydration/decryption failures.
@@ -116,9 +120,50 @@ type Registry struct {
 	//	    http.Error(w, "Internal error", http.StatusInternalServerError)
 	//	}
 	//
-	// The default handler returns 404 for IsNotFound, 400 for IsDecryptionError,
-	// and 500 for all other errors.
+	// The default handler returns 404 for IsNotFound, 403 for IsForbidden,
+	// 400 for IsDecryptionError and IsValidationError, and 500 for all other
+	// errors. It also logs recovered panics (IsPanic) with their stack.
 	OnError func(http.ResponseWriter, *http.Request, error)
+
+	// Authorizer checks the policies components and actions name with
+	// Require, after props are decoded and Hydrate has run:
+	//
+	//	reg.Authorizer = func(ctx context.Context, req hxcmp.AuthRequest) error {
+	//	    if !auth.UserFrom(ctx).Can(req.Policy) {
+	//	        return hxcmp.ErrForbidden
+	//	    }
+	//	    return nil
+	//	}
+	//
+	// When Authorizer is nil, every request that needs a policy is denied.
+	Authorizer Authorizer
+
+	// Logger receives a structured record as each component request
+	// finishes, with the component name and prefix, action, method, status,
+	// duration, error and error category, and props (see
+	// Component.LogProps). Successful requests are logged at Info, client
+	// errors at Warn and server errors at Error.
+	//
+	// When Logger is nil, only failed renders are logged, to slog.Default.
+	// Hydrate and handlers get a logger for the request with Logger(ctx).
+	Logger *slog.Logger
+
+	// Observer, when set, is told how long each stage of a component
+	// request took and whether it failed. Like OnError, it is installed on
+	// components when they are added, so set it before calling Add.
+	// Components served without generated code are not observed. The
+	// registry's own metrics are collected alongside it; see Metrics.
+	Observer Observer
+
+	// Strict makes Add panic when a component's generated code is out of
+	// date: an action registered in its constructor has no generated
+	// dispatch, or is generated with a different method. Such actions would
+	// 404 at runtime. When Strict is false a warning is logged to Logger
+	// instead.
+	//
+	// Enable Strict in development and tests so a forgotten
+	// 'hxcmp generate' fails at startup.
+	Strict bool
 }
 
 // NewRegistry creates a new component registry with the given encryption key.
@@ -138,15 +183,26 @@ func NewRegistry(encryptionKey []byte) *Registry {
 		mux:        http.NewServeMux(),
 		encoder:    enc,
 		components: make(map[string]any),
+		metrics:    newMetrics(),
+		cache:      NewRenderCache(DefaultCacheEntries),
 	}
 
 	// Default error handler - categorizes by error type
 	reg.OnError = func(w http.ResponseWriter, r *http.Request, err error) {
+		var perr *PanicError
+		if errors.As(err, &perr) {
+			// net/http would have logged the panic; keep it visible
+			Logger(r.Context()).Error(perr.Error(), slog.String("stack", string(perr.Stack)))
+		}
 		if IsNotFound(err) {
 			http.Error(w, "Not found", http.StatusNotFound)
 			return
 		}
-		if IsDecryptionError(err) {
+		if IsForbidden(err) {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		if IsDecryptionError(err) || IsValidationError(err) {
 			http.Error(w, "Bad request", http.StatusBadRequest)
 			return
 		}
@@ -161,6 +217,12 @@ func (reg *Registry) Encoder() *Encoder {
 	return reg.encoder
 }
 
+// Cache returns the render cache shared by the registry's components. Only
+// components configured with Component.Cache store renders in it.
+func (reg *Registry) Cache() *RenderCache {
+	return reg.cache
+}
+
 // Add registers components with the registry.
 //
 // Components must embed *hxcmp.Component[P] and implement Hydrater and Renderer.
@@ -194,7 +256,9 @@ func (reg *Registry) registerComponent(comp any) {
 		if _, exists := reg.components[prefix]; exists {
 			panic(fmt.Sprintf("hxcmp: prefix collision for %q", prefix))
 		}
+		reg.checkGenerated(comp)
 		reg.components[prefix] = comp
+		reg.registerMetrics(comp)
 
 		// Set the encoder on the embedded Component via reflection.
 		// Generated code accesses the encoder via c.Component.Encoder().
@@ -202,9 +266,7 @@ func (reg *Registry) registerComponent(comp any) {
 
 		// Register the route pattern
 		pattern := prefix + "/"
-		reg.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
-			hxc.HXServeHTTP(w, r)
-		})
+		reg.mux.Handle(pattern, reg.logRequests(comp, reg.recoverPanics(comp, wrapComponent(comp, http.HandlerFunc(hxc.HXServeHTTP)))))
 		return
 	}
 
@@ -213,9 +275,31 @@ func (reg *Registry) registerComponent(comp any) {
 	reg.registerComponentReflection(comp)
 }
 
-// setEncoderOnComponent sets the encoder and error handler on a component's embedded Component field.
-// This is necessary because generated code accesses the encoder via c.Component.Encoder()
-// and the error handler via c.Component.OnError().
+// checkGenerated reports actions the component's generated code doesn't
+// dispatch, panicking in strict mode and otherwise logging a warning to
+// Logger, or slog.Default when it is nil.
+func (reg *Registry) checkGenerated(comp any) {
+	problems := staleActions(comp)
+	if len(problems) == 0 {
+		return
+	}
+	if reg.Strict {
+		panic(fmt.Sprintf("hxcmp: %T: generated code is out of date, run 'hxcmp generate': %s",
+			comp, strings.Join(problems, "; ")))
+	}
+	logger := reg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger.Warn("hxcmp: generated code is out of date, run 'hxcmp generate'",
+		slog.String("component", fmt.Sprintf("%T", comp)),
+		slog.String("problems", strings.Join(problems, "; ")))
+}
+
+// setEncoderOnComponent sets the encoder, error handler, observer, authorizer and
+// render cache on a component's embedded Component field. This is necessary because generated code accesses the encoder
+// via c.Component.Encoder(), the error handler via c.Component.OnError() and the
+// authorizer via c.Authorize().
 func (reg *Registry) setEncoderOnComponent(comp any) {
 	val := reflect.ValueOf(comp)
 	if val.Kind() != reflect.Ptr {
@@ -241,8 +325,69 @@ func (reg *Registry) setEncoderOnComponent(comp any) {
 	// Call SetOnError on the embedded Component to enable centralized error handling
 	setOnErrorMethod := compField.MethodByName("SetOnError")
 	if setOnErrorMethod.IsValid() {
-		setOnErrorMethod.Call([]reflect.Value{reflect.ValueOf(reg.OnError)})
+		setOnErrorMethod.Call([]reflect.Value{reflect.ValueOf(reg.errorHandler(comp, reg.OnError))})
+	}
+
+	// Call SetObserver on the embedded Component to collect metrics and
+	// report lifecycle stages
+	var observer Observer = reg.metrics
+	if reg.Observer != nil {
+		observer = multiObserver{reg.metrics, reg.Observer}
+	}
+	setObserverMethod := compField.MethodByName("SetObserver")
+	if setObserverMethod.IsValid() {
+		setObserverMethod.Call([]reflect.Value{reflect.ValueOf(&observer).Elem()})
+	}
+
+	// Call SetAuthorizer with a check that reads reg.Authorizer per request,
+	// so it may be set after components are added
+	setAuthorizerMethod := compField.MethodByName("SetAuthorizer")
+	if setAuthorizerMethod.IsValid() {
+		setAuthorizerMethod.Call([]reflect.Value{reflect.ValueOf(Authorizer(reg.authorize))})
+	}
+
+	// Call SetCache so components share the registry's render cache
+	setCacheMethod := compField.MethodByName("SetCache")
+	if setCacheMethod.IsValid() {
+		setCacheMethod.Call([]reflect.Value{reflect.ValueOf(reg.cache)})
+	}
+}
+
+// errorHandler wraps onError to count errors in the registry's metrics and
+// note them for the request log before handling them.
+func (reg *Registry) errorHandler(comp any, onError ErrorHandler) ErrorHandler {
+	ar, ok := comp.(actionRouter)
+	if !ok {
+		return onError
+	}
+	return func(w http.ResponseWriter, r *http.Request, err error) {
+		reg.metrics.recordError(ar.Prefix(), ar.actionFor(r), err)
+		recordError(r.Context(), err)
+		onError(w, r, err)
+	}
+}
+
+// registerMetrics creates metrics for a component's routes.
+func (reg *Registry) registerMetrics(comp any) {
+	d, ok := comp.(describer)
+	if !ok {
+		return
+	}
+	desc := d.describe()
+	actions := make([]string, len(desc.Actions))
+	for i, a := range desc.Actions {
+		actions[i] = a.Name
 	}
+	reg.metrics.register(desc.Prefix, actions)
+}
+
+// authorize checks a policy with the registry's Authorizer, denying it when
+// none is set.
+func (reg *Registry) authorize(ctx context.Context, req AuthRequest) error {
+	if reg.Authorizer == nil {
+		return fmt.Errorf("%w: no Registry.Authorizer to check policy %q", ErrForbidden, req.Policy)
+	}
+	return reg.Authorizer(ctx, req)
 }
 
 // registerComponentReflection uses reflection to register a component without
@@ -290,9 +435,18 @@ func (reg *Registry) registerComponentReflection(comp any) {
 
 	// Register a catch-all route for this component
 	pattern := prefix + "/"
-	reg.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+	reg.mux.Handle(pattern, reg.logRequests(comp, reg.recoverPanics(comp, wrapComponent(comp, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		reg.handleRequest(comp, compField, w, r)
-	})
+	})))))
+}
+
+// wrapComponent applies the middleware added with Component.Use and
+// ActionBuilder.Use around a component's dispatch.
+func wrapComponent(comp any, h http.Handler) http.Handler {
+	if mp, ok := comp.(middlewareProvider); ok {
+		return mp.wrapHandler(h)
+	}
+	return h
 }
 
 // findEmbeddedComponent finds the embedded *Component[P] field via reflection.
@@ -342,7 +496,7 @@ func (reg *Registry) handleRequest(comp any, compField reflect.Value, w http.Res
 	// Create a new props value via reflection
 	propsType := reg.getPropsType(compField)
 	if propsType == nil {
-		reg.OnError(w, r, fmt.Errorf("cannot determine props type"))
+		reg.reflectError(comp, w, r, fmt.Errorf("cannot determine props type"))
 		return
 	}
 	propsPtr := reflect.New(propsType)
@@ -352,7 +506,7 @@ func (reg *Registry) handleRequest(comp any, compField reflect.Value, w http.Res
 		// Get the decoder method if it exists
 		if decoder, ok := propsPtr.Interface().(Decodable); ok {
 			if err := reg.encoder.Decode(encoded, reg.isSensitive(compField), decoder); err != nil {
-				reg.OnError(w, r, WrapDecodeError(err))
+				reg.reflectError(comp, w, r, WrapDecodeError(err))
 				return
 			}
 		}
@@ -366,16 +520,20 @@ func (reg *Registry) handleRequest(comp any, compField reflect.Value, w http.Res
 			propsPtr,
 		})
 		if len(results) > 0 && !results[0].IsNil() {
-			reg.OnError(w, r, results[0].Interface().(error))
+			reg.reflectError(comp, w, r, WrapHydrateError(results[0].Interface().(error)))
 			return
 		}
 	}
 
 	props := propsPtr.Elem()
+	compField.MethodByName("LogProps").Call([]reflect.Value{reflect.ValueOf(r.Context()), props})
 
 	// Route based on method and path
 	if r.Method == http.MethodGet && (path == "" || path == "/") {
 		// GET / - render
+		if !reg.reflectAuthorize(comp, compField, "", props, w, r) {
+			return
+		}
 		reg.reflectRender(comp, props, w, r)
 		return
 	}
@@ -403,14 +561,15 @@ func (reg *Registry) handleRequest(comp any, compField reflect.Value, w http.Res
 					}
 				}
 
-				// Get the handler
-				handlerField := actionDef.Elem().FieldByName("handler")
-				if !handlerField.IsValid() {
-					continue
-				}
+				// Get the handler; the actionDef field is unexported, so it
+				// can't be read with Interface
+				handler := compField.MethodByName("Handler").Call([]reflect.Value{key})[0]
 
 				// Invoke the handler via reflection
-				reg.reflectInvokeHandler(comp, handlerField.Interface(), props, w, r)
+				if !reg.reflectAuthorize(comp, compField, actionName, props, w, r) {
+					return
+				}
+				reg.reflectInvokeHandler(comp, handler.Interface(), props, w, r)
 				return
 			}
 		}
@@ -419,16 +578,38 @@ func (reg *Registry) handleRequest(comp any, compField reflect.Value, w http.Res
 	http.NotFound(w, r)
 }
 
+// reflectAuthorize checks the policies for an action via reflection,
+// reporting denials to OnError. It returns whether the request may proceed.
+func (reg *Registry) reflectAuthorize(comp any, compField reflect.Value, action string, props reflect.Value, w http.ResponseWriter, r *http.Request) bool {
+	results := compField.MethodByName("Authorize").Call([]reflect.Value{
+		reflect.ValueOf(r.Context()),
+		reflect.ValueOf(action),
+		props,
+	})
+	if !results[0].IsNil() {
+		reg.reflectError(comp, w, r, results[0].Interface().(error))
+		return false
+	}
+	return true
+}
+
+// reflectError reports an error to OnError through errorHandler, so the
+// reflection path counts errors in metrics and the request log like
+// generated code does.
+func (reg *Registry) reflectError(comp any, w http.ResponseWriter, r *http.Request, err error) {
+	reg.errorHandler(comp, reg.OnError)(w, r, err)
+}
+
 // getPropsType extracts the props type from a Component[P] field.
 func (reg *Registry) getPropsType(compField reflect.Value) reflect.Type {
-	// The Component[P] has a method that uses P - we can extract it from Refresh's signature
-	refreshMethod := compField.MethodByName("Refresh")
-	if !refreshMethod.IsValid() {
+	// The Component[P] has a method that uses P - we can extract it from Lazy's signature
+	lazyMethod := compField.MethodByName("Lazy")
+	if !lazyMethod.IsValid() {
 		return nil
 	}
-	// Refresh takes (props P) and returns *Action
-	// The first (and only) input parameter is the props type
-	methodType := refreshMethod.Type()
+	// Lazy takes (props P, placeholder) and returns a templ.Component
+	// The first input parameter is the props type
+	methodType := lazyMethod.Type()
 	if methodType.NumIn() > 0 {
 		return methodType.In(0)
 	}
@@ -452,7 +633,7 @@ func (reg *Registry) isSensitive(compField reflect.Value) bool {
 func (reg *Registry) reflectRender(comp any, props reflect.Value, w http.ResponseWriter, r *http.Request) {
 	renderMethod := reflect.ValueOf(comp).MethodByName("Render")
 	if !renderMethod.IsValid() {
-		reg.OnError(w, r, fmt.Errorf("component does not implement Render"))
+		reg.reflectError(comp, w, r, fmt.Errorf("component does not implement Render"))
 		return
 	}
 
@@ -462,7 +643,7 @@ func (reg *Registry) reflectRender(comp any, props reflect.Value, w http.Respons
 	})
 
 	if len(results) == 0 {
-		reg.OnError(w, r, fmt.Errorf("Render returned no value"))
+		reg.reflectError(comp, w, r, fmt.Errorf("Render returned no value"))
 		return
 	}
 
@@ -471,14 +652,14 @@ func (reg *Registry) reflectRender(comp any, props reflect.Value, w http.Respons
 		Render(context.Context, io.Writer) error
 	})
 	if !ok {
-		reg.OnError(w, r, fmt.Errorf("Render did not return a templ.Component"))
+		reg.reflectError(comp, w, r, fmt.Errorf("Render did not return a templ.Component"))
 		return
 	}
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	if err := templComp.Render(r.Context(), w); err != nil {
-		// Already started writing, just log
-		fmt.Printf("hxcmp: render error: %v\n", err)
+	if err := writeRendered(w, r, 0, templComp, false, false); err != nil {
+		recordRenderError(r.Context(), err, true)
+		reg.reflectError(comp, w, r, err)
 	}
 }
 
@@ -486,7 +667,7 @@ func (reg *Registry) reflectRender(comp any, props reflect.Value, w http.Respons
 func (reg *Registry) reflectInvokeHandler(comp any, handler any, props reflect.Value, w http.ResponseWriter, r *http.Request) {
 	handlerVal := reflect.ValueOf(handler)
 	if !handlerVal.IsValid() || handlerVal.Kind() != reflect.Func {
-		reg.OnError(w, r, fmt.Errorf("invalid handler"))
+		reg.reflectError(comp, w, r, fmt.Errorf("invalid handler"))
 		return
 	}
 
@@ -519,7 +700,7 @@ func (reg *Registry) reflectInvokeHandler(comp any, handler any, props reflect.V
 			})
 		}
 	default:
-		reg.OnError(w, r, fmt.Errorf("unsupported handler signature"))
+		reg.reflectError(comp, w, r, fmt.Errorf("unsupported handler signature"))
 		return
 	}
 
@@ -539,7 +720,7 @@ func (reg *Registry) processReflectResult(comp any, result reflect.Value, props
 	if getErrMethod.IsValid() {
 		errResult := getErrMethod.Call(nil)
 		if len(errResult) > 0 && !errResult[0].IsNil() {
-			reg.OnError(w, r, errResult[0].Interface().(error))
+			reg.reflectError(comp, w, r, errResult[0].Interface().(error))
 			return
 		}
 	}
@@ -595,9 +776,13 @@ func (reg *Registry) Handler() http.Handler {
 type MountOption func(*mountOptions)
 
 type mountOptions struct {
-	key     []byte
-	path    string
-	onError func(http.ResponseWriter, *http.Request, error)
+	key        []byte
+	path       string
+	onError    func(http.ResponseWriter, *http.Request, error)
+	authorizer Authorizer
+	observer   Observer
+	logger     *slog.Logger
+	strict     bool
 }
 
 // WithKey sets the encryption key for the registry.
@@ -624,6 +809,37 @@ func WithOnError(handler func(http.ResponseWriter, *http.Request, error)) MountO
 	}
 }
 
+// WithAuthorizer sets the policy check for the registry. See
+// Registry.Authorizer.
+func WithAuthorizer(authorizer Authorizer) MountOption {
+	return func(o *mountOptions) {
+		o.authorizer = authorizer
+	}
+}
+
+// WithObserver sets the lifecycle observer for the registry. See
+// Registry.Observer.
+func WithObserver(observer Observer) MountOption {
+	return func(o *mountOptions) {
+		o.observer = observer
+	}
+}
+
+// WithLogger sets the request logger for the registry. See Registry.Logger.
+func WithLogger(logger *slog.Logger) MountOption {
+	return func(o *mountOptions) {
+		o.logger = logger
+	}
+}
+
+// WithStrict makes the registry panic when a component's generated code is
+// out of date. See Registry.Strict.
+func WithStrict() MountOption {
+	return func(o *mountOptions) {
+		o.strict = true
+	}
+}
+
 // Mount creates a registry, sets it as the default, and mounts the handler.
 //
 // This is the simplest way to initialize hxcmp:
@@ -660,6 +876,10 @@ func Mount(mux *http.ServeMux, opts ...MountOption) *Registry {
 	if options.onError != nil {
 		reg.OnError = options.onError
 	}
+	reg.Authorizer = options.authorizer
+	reg.Observer = options.observer
+	reg.Logger = options.logger
+	reg.Strict = options.strict
 
 	SetDefault(reg)
 	mux.Handle(options.path, reg.Handler())
@@ -0,0 +1,171 @@
+package hxcmp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+// widgetProps is the props type for the widget test component.
+type widgetProps struct {
+	ID string
+}
+
+func (p widgetProps) HXEncode() map[string]any {
+	return map[string]any{"id": p.ID}
+}
+
+func (p *widgetProps) HXDecode(m map[string]any) error {
+	if v, ok := m["id"].(string); ok {
+		p.ID = v
+	}
+	return nil
+}
+
+// widget is a hand-written equivalent of a generated component, used to
+// exercise the registry without running the generator.
+type widget struct {
+	*Component[widgetProps]
+}
+
+func newWidget(name string) *widget {
+	c := &widget{Component: New[widgetProps](name)}
+	c.Action("save", c.handleSave)
+	c.Action("remove", c.handleSave).Method(http.MethodDelete)
+	return c
+}
+
+func (c *widget) Hydrate(ctx context.Context, props *widgetProps) error {
+	if props.ID == "missing" {
+		return ErrNotFound
+	}
+	if props.ID == "panic" {
+		panic("hydrate exploded")
+	}
+	return nil
+}
+
+func (c *widget) Render(ctx context.Context, props widgetProps) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, `<div class="widget">`+props.ID+`</div>`)
+		if props.ID == "unrenderable" {
+			return errors.New("template exploded")
+		}
+		return err
+	})
+}
+
+func (c *widget) handleSave(ctx context.Context, props widgetProps) Result[widgetProps] {
+	Logger(ctx).Info("saving widget")
+	return OK(props).Trigger("widget-saved")
+}
+
+// render renders the widget like generated dispatch.
+func (c *widget) render(w http.ResponseWriter, r *http.Request, action string, props widgetProps) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := c.WriteRender(w, r, action, 0, c.Render(r.Context(), props)); err != nil {
+		c.OnError()(w, r, err)
+	}
+}
+
+func (c *widget) HXPrefix() string {
+	return c.Prefix()
+}
+
+func (c *widget) HXServeHTTP(w http.ResponseWriter, r *http.Request) {
+	encoded := r.URL.Query().Get("p")
+	if encoded == "" && r.Method != http.MethodGet {
+		if err := r.ParseForm(); err == nil {
+			encoded = r.FormValue("p")
+		}
+	}
+	var props widgetProps
+	if encoded != "" {
+		if err := c.Component.Encoder().Decode(encoded, c.IsSensitive(), &props); err != nil {
+			c.OnError()(w, r, WrapDecodeError(err))
+			return
+		}
+	}
+	c.LogProps(r.Context(), props)
+	if err := c.Hydrate(r.Context(), &props); err != nil {
+		c.OnError()(w, r, err)
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, c.HXPrefix())
+	action := strings.TrimPrefix(path, "/")
+	if err := c.Authorize(r.Context(), action, props); err != nil {
+		c.OnError()(w, r, err)
+		return
+	}
+	switch r.Method + " " + path {
+	case "GET /", "GET ":
+		c.render(w, r, "", props)
+	case "POST /save", "DELETE /remove":
+		result := c.handleSave(r.Context(), props)
+		w.Header().Set("HX-Trigger", BuildTriggerHeader(result.GetTrigger(), result.GetTriggerData()))
+		c.render(w, r, action, result.GetProps())
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// widgetURL builds a request URL for the widget with encoded props.
+func widgetURL(t *testing.T, reg *Registry, c *widget, action string, props widgetProps) string {
+	t.Helper()
+	encoded, err := reg.Encoder().Encode(props, c.IsSensitive())
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	return c.Prefix() + "/" + action + "?p=" + encoded
+}
+
+func TestRegistryAddServesComponent(t *testing.T) {
+	reg := NewRegistry(make([]byte, 32))
+	c := newWidget("widget")
+	reg.Add(c)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, widgetURL(t, reg, c, "", widgetProps{ID: "42"}), nil)
+	reg.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if !strings.Contains(rec.Body.String(), `<div class="widget">42</div>`) {
+		t.Errorf("unexpected body: %s", rec.Body.String())
+	}
+}
+
+func TestRegistryAddRoutesErrorsToOnError(t *testing.T) {
+	reg := NewRegistry(make([]byte, 32))
+	c := newWidget("widget")
+	reg.Add(c)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, widgetURL(t, reg, c, "", widgetProps{ID: "missing"}), nil)
+	reg.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestRegistryAddPrefixCollision(t *testing.T) {
+	reg := NewRegistry(make([]byte, 32))
+	c := newWidget("widget")
+	reg.Add(c)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic on prefix collision")
+		}
+	}()
+	reg.Add(c)
+}
@@ -0,0 +1,91 @@
+package hxcmp
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/a-h/templ"
+)
+
+// maxPooledBuffer is the largest render buffer returned to the pool, so one
+// very large fragment doesn't pin its memory.
+const maxPooledBuffer = 256 << 10
+
+var bufferPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// Streaming makes the component write its Render output straight to the
+// response instead of buffering it.
+//
+// By default, output is rendered into a pooled buffer so a failing template
+// is reported to OnError, with ErrRenderFailed, instead of sending a
+// truncated fragment, and so Content-Length can be set. Streaming suits
+// very large fragments, at the cost that render errors after output has
+// started can only be logged.
+func (c *Component[P]) Streaming() *Component[P] {
+	c.streaming = true
+	return c
+}
+
+// IsStreaming returns whether the component streams its Render output.
+func (c *Component[P]) IsStreaming() bool {
+	return c.streaming
+}
+
+// WriteRender renders tmpl as the response for action with status, or 200
+// when status is 0, observing the render stage. Generated dispatch calls it
+// with the output of Render.
+//
+// It returns an error wrapping ErrRenderFailed, for the caller to pass to
+// OnError, when rendering fails before anything was written.
+func (c *Component[P]) WriteRender(w http.ResponseWriter, r *http.Request, action string, status int, tmpl templ.Component) error {
+	start := time.Now()
+	// Versioner components already set an ETag without rendering
+	etag := c.etags && action == "" && r.Method == http.MethodGet && w.Header().Get("ETag") == ""
+	err := writeRendered(w, r, status, tmpl, c.streaming, etag)
+	c.Observe(r, StageRender, action, start, err)
+	if c.streaming {
+		// Output has started; the error is logged with the request
+		return nil
+	}
+	return err
+}
+
+// writeRendered renders tmpl to w with status. Unless streaming, it renders
+// into a pooled buffer first and writes nothing if rendering fails, and
+// with etag it answers a matching If-None-Match with 304 Not Modified.
+func writeRendered(w http.ResponseWriter, r *http.Request, status int, tmpl templ.Component, streaming, etag bool) error {
+	ctx := r.Context()
+	if streaming {
+		if status != 0 {
+			w.WriteHeader(status)
+		}
+		return WrapRenderError(tmpl.Render(ctx, w))
+	}
+
+	buf := bufferPool.Get().(*bytes.Buffer)
+	defer func() {
+		if buf.Cap() <= maxPooledBuffer {
+			buf.Reset()
+			bufferPool.Put(buf)
+		}
+	}()
+	if err := tmpl.Render(ctx, buf); err != nil {
+		return WrapRenderError(err)
+	}
+	if etag && (status == 0 || status == http.StatusOK) && NotModified(w, r, ETag(buf.Bytes())) {
+		return nil
+	}
+
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	if status != 0 {
+		w.WriteHeader(status)
+	}
+	// A failed write means the client has gone; there is no one to tell
+	w.Write(buf.Bytes())
+	return nil
+}
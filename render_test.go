@@ -0,0 +1,58 @@
+package hxcmp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+// failingTemplate writes markup and then fails, like a template whose
+// expression errors halfway through.
+var failingTemplate = templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+	io.WriteString(w, "<div>partial")
+	return errors.New("template exploded")
+})
+
+func TestWriteRender(t *testing.T) {
+	c := New[widgetProps]("widget")
+	r := httptest.NewRequest(http.MethodGet, c.Prefix()+"/", nil)
+
+	rec := httptest.NewRecorder()
+	tmpl := templ.Raw("<p>hello</p>")
+	if err := c.WriteRender(rec, r, "", http.StatusCreated, tmpl); err != nil {
+		t.Fatalf("WriteRender() error = %v", err)
+	}
+	if rec.Code != http.StatusCreated || rec.Body.String() != "<p>hello</p>" || rec.Header().Get("Content-Length") != "12" {
+		t.Errorf("response = %d %v %q", rec.Code, rec.Header(), rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	err := c.WriteRender(rec, r, "", 0, failingTemplate)
+	if !IsRenderError(err) || err.Error() != "hxcmp: render failed: template exploded" {
+		t.Errorf("WriteRender() error = %v, want a render error", err)
+	}
+	if rec.Body.Len() != 0 || rec.Header().Get("Content-Length") != "" {
+		t.Errorf("failed render wrote %v %q", rec.Header(), rec.Body.String())
+	}
+}
+
+func TestWriteRenderStreaming(t *testing.T) {
+	c := New[widgetProps]("widget").Streaming()
+	if !c.IsStreaming() {
+		t.Fatal("IsStreaming() = false after Streaming()")
+	}
+	r := httptest.NewRequest(http.MethodGet, c.Prefix()+"/", nil)
+
+	rec := httptest.NewRecorder()
+	if err := c.WriteRender(rec, r, "", 0, failingTemplate); err != nil {
+		t.Errorf("WriteRender() error = %v, want nil once output has started", err)
+	}
+	if rec.Body.String() != "<div>partial" || rec.Header().Get("Content-Length") != "" {
+		t.Errorf("streamed response = %v %q", rec.Header(), rec.Body.String())
+	}
+}
@@ -1,5 +1,7 @@
 package hxcmp
 
+import "github.com/a-h/templ"
+
 // Result[P] is returned from action handlers to control rendering and side effects.
 //
 // Result is a fluent builder that enables handlers to specify redirects,
@@ -33,6 +35,8 @@ type Result[P any] struct {
 	redirect string
 	trigger  string
 	triggerData map[string]any
+	invalidate  []string
+	oob         []OOBSwap
 	headers     map[string]string
 	status      int
 	skip        bool
@@ -104,6 +108,44 @@ func (r Result[P]) Trigger(event string, data ...map[string]any) Result[P] {
 	return r
 }
 
+// Invalidate evicts the renders cached with any of tags (see
+// Component.Cache) when the action succeeds. Tags named like the event
+// passed to Trigger are evicted without it:
+//
+//	return hxcmp.OK(props).Invalidate("stats", "todo:"+props.ID)
+func (r Result[P]) Invalidate(tags ...string) Result[P] {
+	r.invalidate = append(r.invalidate[:len(r.invalidate):len(r.invalidate)], tags...)
+	return r
+}
+
+// OOB renders component with props after the response and swaps it into
+// the page out of band, replacing the element with the id of its root, so
+// one action can refresh other components without extra requests:
+//
+//	return hxcmp.OK(props).
+//	    OOB(sidebar, sidebar.Props{}).
+//	    OOB(stats, stats.Props{})
+//
+// The component is hydrated like for its own requests and its Render output
+// must have a single root element with an id. Swaps are ignored for Skip and
+// Redirect results.
+func (r Result[P]) OOB(component OOBComponent, props any) Result[P] {
+	return r.addOOB(OOBSwap{component: component, props: props})
+}
+
+// OOBTemplate swaps tmpl into the element with id out of band, replacing
+// its content:
+//
+//	return hxcmp.OK(props).OOBTemplate("flash", Flash("Saved"))
+func (r Result[P]) OOBTemplate(id string, tmpl templ.Component) Result[P] {
+	return r.addOOB(OOBSwap{id: id, tmpl: tmpl})
+}
+
+func (r Result[P]) addOOB(swap OOBSwap) Result[P] {
+	r.oob = append(r.oob[:len(r.oob):len(r.oob)], swap)
+	return r
+}
+
 // PushURL updates the browser URL via HX-Push-Url header.
 //
 //	return hxcmp.OK(props).PushURL("/todos?status=pending")
@@ -160,6 +202,21 @@ func (r Result[P]) GetTriggerData() map[string]any {
 	return r.triggerData
 }
 
+// GetInvalidate returns the cache tags to evict: those passed to Invalidate
+// and the trigger event name, if any.
+func (r Result[P]) GetInvalidate() []string {
+	tags := append([]string(nil), r.invalidate...)
+	if r.trigger != "" {
+		tags = append(tags, r.trigger)
+	}
+	return tags
+}
+
+// GetOOB returns the out-of-band swaps added with OOB and OOBTemplate.
+func (r Result[P]) GetOOB() []OOBSwap {
+	return r.oob
+}
+
 // GetHeaders returns the response headers.
 func (r Result[P]) GetHeaders() map[string]string {
 	return r.headers
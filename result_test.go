@@ -2,6 +2,7 @@ package hxcmp
 
 import (
 	"errors"
+	"reflect"
 	"testing"
 )
 
@@ -69,6 +70,35 @@ func TestResultTrigger(t *testing.T) {
 	}
 }
 
+func TestResultInvalidate(t *testing.T) {
+	props := testResultProps{ID: 1}
+	base := OK(props).Invalidate("a")
+	r := base.Invalidate("b").Trigger("itemUpdated")
+
+	if got := r.GetInvalidate(); !reflect.DeepEqual(got, []string{"a", "b", "itemUpdated"}) {
+		t.Errorf("GetInvalidate() = %q", got)
+	}
+	if got := base.Invalidate("c").GetInvalidate(); !reflect.DeepEqual(got, []string{"a", "c"}) {
+		t.Errorf("GetInvalidate() = %q, want results built from base to be independent", got)
+	}
+	if got := OK(props).GetInvalidate(); len(got) != 0 {
+		t.Errorf("GetInvalidate() = %q, want none", got)
+	}
+}
+
+func TestResultOOB(t *testing.T) {
+	props := testResultProps{ID: 1}
+	base := OK(props).OOBTemplate("a", nil)
+	r := base.OOBTemplate("b", nil)
+
+	if got := r.GetOOB(); len(got) != 2 || got[0].id != "a" || got[1].id != "b" {
+		t.Errorf("GetOOB() = %+v", got)
+	}
+	if got := base.OOBTemplate("c", nil).GetOOB(); got[1].id != "c" || r.GetOOB()[1].id != "b" {
+		t.Errorf("GetOOB() = %+v, want results built from base to be independent", got)
+	}
+}
+
 func TestResultHeader(t *testing.T) {
 	props := testResultProps{ID: 1}
 	r := OK(props).
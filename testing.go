@@ -3,10 +3,12 @@ package hxcmp
 import (
 	"bytes"
 	"context"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"strings"
+	"sync"
 
 	"github.com/a-h/templ"
 )
@@ -135,23 +137,7 @@ func TestAction(
 	rec := httptest.NewRecorder()
 	comp.HXServeHTTP(rec, req)
 
-	result := &TestResult{
-		HTML:       rec.Body.String(),
-		StatusCode: rec.Code,
-		Headers:    rec.Header(),
-	}
-
-	// Parse triggered events from HX-Trigger header
-	if trigger := rec.Header().Get("HX-Trigger"); trigger != "" {
-		result.TriggeredEvents = parseTriggerHeader(trigger)
-	}
-
-	// Parse redirect from HX-Redirect header
-	if redirect := rec.Header().Get("HX-Redirect"); redirect != "" {
-		result.RedirectURL = redirect
-	}
-
-	return result, nil
+	return newTestResult(rec), nil
 }
 
 // TestActionWithContext simulates an action request with a custom context.
@@ -192,23 +178,7 @@ func TestActionWithContext(
 	rec := httptest.NewRecorder()
 	comp.HXServeHTTP(rec, req)
 
-	result := &TestResult{
-		HTML:       rec.Body.String(),
-		StatusCode: rec.Code,
-		Headers:    rec.Header(),
-	}
-
-	// Parse triggered events from HX-Trigger header
-	if trigger := rec.Header().Get("HX-Trigger"); trigger != "" {
-		result.TriggeredEvents = parseTriggerHeader(trigger)
-	}
-
-	// Parse redirect from HX-Redirect header
-	if redirect := rec.Header().Get("HX-Redirect"); redirect != "" {
-		result.RedirectURL = redirect
-	}
-
-	return result, nil
+	return newTestResult(rec), nil
 }
 
 // TestGet simulates a GET request (render) against an HXComponent.
@@ -231,6 +201,27 @@ func TestPost(comp HXComponent, url string, formData map[string]string) (*TestRe
 	return TestAction(comp, url, http.MethodPost, formData)
 }
 
+// newTestResult builds a TestResult from a recorded response.
+func newTestResult(rec *httptest.ResponseRecorder) *TestResult {
+	result := &TestResult{
+		HTML:       rec.Body.String(),
+		StatusCode: rec.Code,
+		Headers:    rec.Header(),
+	}
+
+	// Parse triggered events from HX-Trigger header
+	if trigger := rec.Header().Get("HX-Trigger"); trigger != "" {
+		result.TriggeredEvents = parseTriggerHeader(trigger)
+	}
+
+	// Parse redirect from HX-Redirect header
+	if redirect := rec.Header().Get("HX-Redirect"); redirect != "" {
+		result.RedirectURL = redirect
+	}
+
+	return result
+}
+
 // HTMLContains checks if the HTML contains a substring.
 func (r *TestResult) HTMLContains(substr string) bool {
 	return strings.Contains(r.HTML, substr)
@@ -456,23 +447,104 @@ func (b *TestRequestBuilder) Execute(comp HXComponent) (*TestResult, error) {
 	rec := httptest.NewRecorder()
 	comp.HXServeHTTP(rec, req)
 
-	result := &TestResult{
-		HTML:       rec.Body.String(),
-		StatusCode: rec.Code,
-		Headers:    rec.Header(),
+	return newTestResult(rec), nil
+}
+
+// testKey is the fixed key returned by TestKey.
+const testKey = "hxcmp-deterministic-test-key-32b"
+
+// TestKey returns a fixed 32-byte key for tests.
+//
+// Using the same key on every run keeps signed URLs stable, so test output
+// and golden files don't change between runs. Never use it in production.
+func TestKey() []byte {
+	return []byte(testKey)
+}
+
+// TestClient sends requests to a single component through a Registry keyed
+// with TestKey, so props are encoded exactly as in production.
+//
+// Generated <Type>TestClient types embed a TestClient and add one typed
+// method per action:
+//
+//	client := NewTaskDetailTestClient(NewTaskDetail(repo))
+//	result := client.Update(props, TaskForm{Title: "new title"})
+//	if !result.IsOK() {
+//	    t.Fatalf("status = %d", result.StatusCode)
+//	}
+//
+// Use TestClient directly for components without generated code or to send
+// hand-built requests.
+type TestClient struct {
+	reg     *Registry
+	ctx     context.Context
+	headers http.Header
+}
+
+// NewTestClient registers comp with a new Registry keyed with TestKey and
+// returns a client for it.
+//
+// Registration sets the component's encoder, so a component passed here
+// should not also be registered with another Registry.
+func NewTestClient(comp HXComponent) *TestClient {
+	reg := NewRegistry(TestKey())
+	reg.Add(comp)
+	return &TestClient{
+		reg:     reg,
+		ctx:     context.Background(),
+		headers: make(http.Header),
 	}
+}
 
-	// Parse triggered events
-	if trigger := rec.Header().Get("HX-Trigger"); trigger != "" {
-		result.TriggeredEvents = parseTriggerHeader(trigger)
+// SetContext sets the context used for subsequent requests.
+func (c *TestClient) SetContext(ctx context.Context) {
+	c.ctx = ctx
+}
+
+// SetAuthorizer sets the Authorizer that checks the policies the component
+// declares with Require. Without one, requests that need a policy are
+// denied, as they are by a Registry.
+func (c *TestClient) SetAuthorizer(authorizer Authorizer) {
+	c.reg.Authorizer = authorizer
+}
+
+// SetHeader sets a header sent with subsequent requests.
+func (c *TestClient) SetHeader(key, value string) {
+	c.headers.Set(key, value)
+}
+
+// Do sends a request to target and returns the recorded response.
+//
+// Form values are sent in an urlencoded body for POST, PUT, and PATCH, and
+// appended to the query string for other methods, matching how HTMX sends
+// parameters.
+func (c *TestClient) Do(method, target string, form url.Values) *TestResult {
+	var body io.Reader
+	if len(form) > 0 {
+		switch method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch:
+			body = strings.NewReader(form.Encode())
+		default:
+			sep := "?"
+			if strings.Contains(target, "?") {
+				sep = "&"
+			}
+			target += sep + form.Encode()
+		}
 	}
 
-	// Parse redirect
-	if redirect := rec.Header().Get("HX-Redirect"); redirect != "" {
-		result.RedirectURL = redirect
+	req := httptest.NewRequest(method, target, body).WithContext(c.ctx)
+	req.Header.Set("HX-Request", "true")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	for k, v := range c.headers {
+		req.Header[k] = v
 	}
 
-	return result, nil
+	rec := httptest.NewRecorder()
+	c.reg.Handler().ServeHTTP(rec, req)
+	return newTestResult(rec)
 }
 
 // MockHydrater wraps a component and provides a custom hydration function.
@@ -521,3 +593,62 @@ func (m *MockHydrater[P]) Render(ctx context.Context, props P) templ.Component {
 func (m *MockHydrater[P]) LastHydratedProps() *P {
 	return m.hydrateProps
 }
+
+// SpanRecorder is an Observer that records spans, standing in for a tracing
+// system in tests:
+//
+//	spans := &hxcmp.SpanRecorder{}
+//	reg.Observer = spans
+//	reg.Add(comp)
+//	// ... serve requests ...
+//	for _, s := range spans.Spans() {
+//	    t.Logf("%s %s took %v", s.Action, s.Stage, s.Duration)
+//	}
+//
+// It is safe for concurrent use.
+type SpanRecorder struct {
+	mu    sync.Mutex
+	spans []Span
+}
+
+// OnDecode records span.
+func (r *SpanRecorder) OnDecode(ctx context.Context, span Span) { r.record(span) }
+
+// OnHydrate records span.
+func (r *SpanRecorder) OnHydrate(ctx context.Context, span Span) { r.record(span) }
+
+// OnHandler records span.
+func (r *SpanRecorder) OnHandler(ctx context.Context, span Span) { r.record(span) }
+
+// OnRender records span.
+func (r *SpanRecorder) OnRender(ctx context.Context, span Span) { r.record(span) }
+
+func (r *SpanRecorder) record(span Span) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans = append(r.spans, span)
+}
+
+// Spans returns the recorded spans in the order they finished.
+func (r *SpanRecorder) Spans() []Span {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Span(nil), r.spans...)
+}
+
+// Stages returns the stage of each recorded span, in order.
+func (r *SpanRecorder) Stages() []Stage {
+	spans := r.Spans()
+	stages := make([]Stage, len(spans))
+	for i, s := range spans {
+		stages[i] = s.Stage
+	}
+	return stages
+}
+
+// Reset discards the recorded spans.
+func (r *SpanRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans = nil
+}
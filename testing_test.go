@@ -5,6 +5,7 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"net/url"
 	"testing"
 
 	"github.com/a-h/templ"
@@ -608,6 +609,61 @@ func TestTestRequestBuilder_WithFormValues(t *testing.T) {
 	}
 }
 
+func TestTestKey(t *testing.T) {
+	if len(TestKey()) != 32 {
+		t.Errorf("len(TestKey()) = %d, want 32", len(TestKey()))
+	}
+
+	// Mutating the returned key must not affect later calls
+	key := TestKey()
+	key[0] = 'x'
+	if TestKey()[0] == 'x' {
+		t.Error("TestKey() returned shared storage")
+	}
+}
+
+func TestTestClient_Do(t *testing.T) {
+	var capturedQuery, capturedForm, capturedHeader string
+	var capturedCtxValue any
+
+	type ctxKey struct{}
+	comp := &mockHXComponent{
+		prefix: "/_hxc/client",
+		handler: func(w http.ResponseWriter, r *http.Request) {
+			capturedQuery = r.URL.RawQuery
+			r.ParseForm()
+			capturedForm = r.PostForm.Get("name")
+			capturedHeader = r.Header.Get("X-Custom")
+			capturedCtxValue = r.Context().Value(ctxKey{})
+			w.Header().Set("HX-Trigger", "saved")
+			w.Write([]byte(`<div>ok</div>`))
+		},
+	}
+
+	client := NewTestClient(comp)
+	client.SetHeader("X-Custom", "custom-value")
+	client.SetContext(context.WithValue(context.Background(), ctxKey{}, "ctx-value"))
+
+	result := client.Do(http.MethodPost, "/_hxc/client/save?p=abc", url.Values{"name": {"new"}})
+	if !result.IsOK() || !result.HTMLContains("ok") || !result.HasEvent("saved") {
+		t.Errorf("POST result = %+v", result)
+	}
+	if capturedForm != "new" || capturedQuery != "p=abc" {
+		t.Errorf("POST form = %q, query = %q", capturedForm, capturedQuery)
+	}
+	if capturedHeader != "custom-value" || capturedCtxValue != "ctx-value" {
+		t.Errorf("header = %q, ctx value = %v", capturedHeader, capturedCtxValue)
+	}
+	if comp.lastMethod != http.MethodPost || comp.lastPath != "/_hxc/client/save" {
+		t.Errorf("routed %s %s", comp.lastMethod, comp.lastPath)
+	}
+
+	client.Do(http.MethodGet, "/_hxc/client/?p=abc", url.Values{"q": {"x"}})
+	if capturedQuery != "p=abc&q=x" {
+		t.Errorf("GET query = %q, want form values appended", capturedQuery)
+	}
+}
+
 func TestMockHydrater(t *testing.T) {
 	baseComp := &mockComponent{}
 